@@ -0,0 +1,124 @@
+package v1
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+const (
+	MessageBatchResultTypeSucceeded = "succeeded"
+	MessageBatchResultTypeErrored   = "errored"
+	MessageBatchResultTypeCanceled  = "canceled"
+	MessageBatchResultTypeExpired   = "expired"
+)
+
+type ResponseBodyMessageBatchResult struct {
+	CustomId string `json:"custom_id"`
+	Result   struct {
+		Type    string                `json:"type"`    // "succeeded", "errored", "canceled" or "expired"
+		Message *ResponseBodyMessages `json:"message"` // succeeded type only
+		Error   *ResponseError        `json:"error"`   // errored type only
+	} `json:"result"`
+}
+
+// MessageBatchResultsReader decodes the JSONL results of a message batch one line at a time.
+type MessageBatchResultsReader struct {
+	r      *bufio.Reader
+	closer io.Closer
+	offset int64
+}
+
+// NewMessageBatchResultsReader reads results from r. offset is the byte offset of r in the
+// results file, so Offset stays correct when resuming.
+func NewMessageBatchResultsReader(r io.Reader, offset int64) *MessageBatchResultsReader {
+	reader := &MessageBatchResultsReader{
+		r:      bufio.NewReader(r),
+		offset: offset,
+	}
+	if closer, ok := r.(io.Closer); ok {
+		reader.closer = closer
+	}
+	return reader
+}
+
+// GetMessageBatchResults streams the results of an ended batch, starting at the byte offset.
+// Pass the Offset of a previous reader to resume where it stopped.
+func (c *Client) GetMessageBatchResults(ctx context.Context, batchId string, offset int64) (*MessageBatchResultsReader, error) {
+	ctx = withRequestInfo(ctx, RequestInfo{Operation: OperationGetMessageBatchResults})
+	req, err := c.newRequest(ctx, "GET", batchesEndpoint+"/"+url.PathEscape(batchId)+"/results", nil)
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	// the span ends when the response headers are received
+	ctx, span := c.startSpan(ctx, RequestInfo{Operation: OperationGetMessageBatchResults})
+	req = req.WithContext(ctx)
+	resp, err := c.send(req)
+	if err != nil {
+		endSpan(span, ResponseMetadata{}, 0, err)
+		return nil, err
+	}
+	metadata := newResponseMetadata(resp.Header, 0)
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		endSpan(span, metadata, resp.StatusCode, nil)
+		return NewMessageBatchResultsReader(resp.Body, offset), nil
+	case http.StatusOK:
+		// the server ignored the Range header, so skip to the offset
+		_, err = io.CopyN(io.Discard, resp.Body, offset)
+		endSpan(span, metadata, resp.StatusCode, err)
+		if err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+		return NewMessageBatchResultsReader(resp.Body, offset), nil
+	}
+	defer resp.Body.Close()
+	err = responseError(resp)
+	endSpan(span, metadata, resp.StatusCode, err)
+	return nil, err
+}
+
+// Next returns the next result, or io.EOF when there are no more results.
+func (r *MessageBatchResultsReader) Next() (ResponseBodyMessageBatchResult, error) {
+	for {
+		line, err := r.r.ReadBytes('\n')
+		if err != nil && (err != io.EOF || len(line) == 0) {
+			return ResponseBodyMessageBatchResult{}, err
+		}
+		start := r.offset
+		r.offset += int64(len(line))
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		var result ResponseBodyMessageBatchResult
+		if err := json.Unmarshal(line, &result); err != nil {
+			return ResponseBodyMessageBatchResult{}, fmt.Errorf("decode result at offset %d: %w", start, err)
+		}
+		return result, nil
+	}
+}
+
+// Offset returns the byte offset of the next result in the results file.
+func (r *MessageBatchResultsReader) Offset() int64 {
+	return r.offset
+}
+
+func (r *MessageBatchResultsReader) Close() error {
+	if r.closer == nil {
+		return nil
+	}
+	return r.closer.Close()
+}
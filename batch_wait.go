@@ -0,0 +1,83 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	defaultWaitForBatchInitialInterval = 5 * time.Second
+	defaultWaitForBatchMaxInterval     = time.Minute
+	defaultWaitForBatchMultiplier      = 2
+	waitForBatchCancelTimeout          = 30 * time.Second
+)
+
+type WaitForBatchOptions struct {
+	InitialInterval time.Duration                  // first polling interval, default 5s
+	MaxInterval     time.Duration                  // upper bound of the polling interval, default 1m
+	Multiplier      float64                        // interval growth after each poll, default 2
+	OnProgress      func(ResponseBodyMessageBatch) // optional, called after each poll
+	CancelOnDone    bool                           // cancel the batch when ctx is done
+}
+
+// WaitForBatch polls the batch with exponential backoff until its processing_status is "ended".
+//
+// When ctx is done, the last polled batch is returned with ctx.Err(). If CancelOnDone is set,
+// the cancellation of the batch is initiated before returning.
+func (c *Client) WaitForBatch(ctx context.Context, batchId string, opts WaitForBatchOptions) (*ResponseBodyMessageBatch, error) {
+	interval := opts.InitialInterval
+	if interval <= 0 {
+		interval = defaultWaitForBatchInitialInterval
+	}
+	maxInterval := opts.MaxInterval
+	if maxInterval <= 0 {
+		maxInterval = defaultWaitForBatchMaxInterval
+	}
+	multiplier := opts.Multiplier
+	if multiplier < 1 {
+		multiplier = defaultWaitForBatchMultiplier
+	}
+
+	var batch *ResponseBodyMessageBatch
+	for {
+		b, err := c.GetMessageBatch(ctx, batchId)
+		if err != nil {
+			if ctx.Err() != nil {
+				return batch, c.cancelWaitForBatch(ctx, batchId, opts)
+			}
+			return batch, err
+		}
+		batch = b
+		if opts.OnProgress != nil {
+			opts.OnProgress(*batch)
+		}
+		if batch.ProcessingStatus == MessageBatchProcessingStatusEnded {
+			return batch, nil
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return batch, c.cancelWaitForBatch(ctx, batchId, opts)
+		case <-timer.C:
+		}
+		interval = time.Duration(float64(interval) * multiplier)
+		if interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+}
+
+// cancelWaitForBatch cancels the batch if requested, and returns the error of the done ctx.
+func (c *Client) cancelWaitForBatch(ctx context.Context, batchId string, opts WaitForBatchOptions) error {
+	if opts.CancelOnDone {
+		cancelCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), waitForBatchCancelTimeout)
+		defer cancel()
+		if _, err := c.CancelMessageBatch(cancelCtx, batchId); err != nil {
+			return errors.Join(ctx.Err(), err)
+		}
+	}
+	return ctx.Err()
+}
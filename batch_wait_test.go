@@ -0,0 +1,103 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWaitForBatch(t *testing.T) {
+	tests := []struct {
+		name         string
+		inProgress   int // polls answered in_progress before the batch has ended
+		cancelAfter  int // polls before the ctx is canceled, 0 never cancels
+		cancelOnDone bool
+		wantStatus   string
+		wantPolls    int
+		wantCanceled bool
+	}{
+		{name: "ended", inProgress: 4, wantStatus: MessageBatchProcessingStatusEnded, wantPolls: 5},
+		{name: "ended on the first poll", inProgress: 0, wantStatus: MessageBatchProcessingStatusEnded, wantPolls: 1},
+		{name: "ctx done", inProgress: 100, cancelAfter: 2, wantStatus: MessageBatchProcessingStatusInProgress, wantPolls: 2},
+		{name: "ctx done with CancelOnDone", inProgress: 100, cancelAfter: 2, cancelOnDone: true, wantStatus: MessageBatchProcessingStatusInProgress, wantPolls: 2, wantCanceled: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var polls []time.Time
+			canceled := false
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+				status := MessageBatchProcessingStatusInProgress
+				switch {
+				case r.Method == "POST" && r.URL.Path == "/v1/messages/batches/msgbatch_1/cancel":
+					canceled = true
+					status = MessageBatchProcessingStatusCanceling
+				case r.Method == "GET" && r.URL.Path == "/v1/messages/batches/msgbatch_1":
+					polls = append(polls, time.Now())
+					if len(polls) > tt.inProgress {
+						status = MessageBatchProcessingStatusEnded
+					}
+				default:
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				json.NewEncoder(w).Encode(ResponseBodyMessageBatch{Id: "msgbatch_1", ProcessingStatus: status})
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			var progress []string
+			batch, err := c.WaitForBatch(ctx, "msgbatch_1", WaitForBatchOptions{
+				InitialInterval: 20 * time.Millisecond,
+				MaxInterval:     50 * time.Millisecond,
+				Multiplier:      2,
+				OnProgress: func(b ResponseBodyMessageBatch) {
+					progress = append(progress, b.ProcessingStatus)
+					if len(progress) == tt.cancelAfter {
+						cancel()
+					}
+				},
+				CancelOnDone: tt.cancelOnDone,
+			})
+
+			if tt.cancelAfter > 0 {
+				if !errors.Is(err, context.Canceled) {
+					t.Errorf("err = %v, want context.Canceled", err)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			// the last polled batch is returned, also when ctx is done
+			if batch == nil || batch.ProcessingStatus != tt.wantStatus {
+				t.Fatalf("batch = %+v, want %s", batch, tt.wantStatus)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if len(polls) != tt.wantPolls || len(progress) != tt.wantPolls {
+				t.Errorf("polls = %d, OnProgress calls = %d, want %d", len(polls), len(progress), tt.wantPolls)
+			}
+			if progress[len(progress)-1] != tt.wantStatus {
+				t.Errorf("last OnProgress status = %s, want %s", progress[len(progress)-1], tt.wantStatus)
+			}
+			if canceled != tt.wantCanceled {
+				t.Errorf("cancel request made = %v, want %v", canceled, tt.wantCanceled)
+			}
+
+			// 20ms doubled each poll and capped at 50ms
+			want := []time.Duration{20, 40, 50, 50}
+			uncapped := []time.Duration{20, 40, 80, 160}
+			for i := 1; i < len(polls); i++ {
+				gap := polls[i].Sub(polls[i-1])
+				if gap < want[i-1]*time.Millisecond || gap >= uncapped[i-1]*time.Millisecond+40*time.Millisecond {
+					t.Errorf("interval %d = %s, want %dms", i, gap, want[i-1])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/url"
+	"time"
+)
+
+const batchesEndpoint = "v1/messages/batches"
+
+const (
+	MessageBatchProcessingStatusInProgress = "in_progress"
+	MessageBatchProcessingStatusCanceling  = "canceling"
+	MessageBatchProcessingStatusEnded      = "ended"
+)
+
+type RequestBodyMessageBatch struct {
+	Requests []RequestBodyMessageBatchRequest `json:"requests"`
+}
+
+type RequestBodyMessageBatchRequest struct {
+	CustomId  string              `json:"custom_id"`
+	Params    RequestBodyMessages `json:"-"`
+	ParamsRaw json.RawMessage     `json:"params"`
+}
+
+type ResponseBodyMessageBatch struct {
+	Id                string                                `json:"id"`
+	Type              string                                `json:"type"`              // always "message_batch"
+	ProcessingStatus  string                                `json:"processing_status"` // "in_progress", "canceling" or "ended"
+	RequestCounts     ResponseBodyMessageBatchRequestCounts `json:"request_counts"`
+	CreatedAt         time.Time                             `json:"created_at"`
+	ExpiresAt         time.Time                             `json:"expires_at"`
+	EndedAt           *time.Time                            `json:"ended_at"`
+	ArchivedAt        *time.Time                            `json:"archived_at"`
+	CancelInitiatedAt *time.Time                            `json:"cancel_initiated_at"`
+	ResultsUrl        string                                `json:"results_url"` // set when processing has ended
+}
+
+type ResponseBodyMessageBatchRequestCounts struct {
+	Processing int64 `json:"processing"`
+	Succeeded  int64 `json:"succeeded"`
+	Errored    int64 `json:"errored"`
+	Canceled   int64 `json:"canceled"`
+	Expired    int64 `json:"expired"`
+}
+
+type ResponseBodyMessageBatchesList struct {
+	Data    []ResponseBodyMessageBatch `json:"data"`
+	HasMore bool                       `json:"has_more"`
+	FirstId string                     `json:"first_id"`
+	LastId  string                     `json:"last_id"`
+}
+
+type ResponseBodyMessageBatchDeleted struct {
+	Id   string `json:"id"`
+	Type string `json:"type"` // always "message_batch_deleted"
+}
+
+// CreateMessageBatch sends a batch of messages requests to be processed asynchronously.
+// The params of each request are serialized in the same way as CreateMessages.
+func (c *Client) CreateMessageBatch(ctx context.Context, body RequestBodyMessageBatch) (*ResponseBodyMessageBatch, error) {
+	jsonBody, err := parseBatchBodyJSON(body)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = withRequestInfo(ctx, RequestInfo{Operation: OperationCreateMessageBatch})
+	req, err := c.newRequest(ctx, "POST", batchesEndpoint, jsonBody)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range body.Requests {
+		addBetas(req, requiredBetas(r.Params)...)
+	}
+
+	var result ResponseBodyMessageBatch
+	_, err = c.do(req, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *Client) GetMessageBatch(ctx context.Context, batchId string) (*ResponseBodyMessageBatch, error) {
+	ctx = withRequestInfo(ctx, RequestInfo{Operation: OperationGetMessageBatch})
+	req, err := c.newRequest(ctx, "GET", batchesEndpoint+"/"+url.PathEscape(batchId), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result ResponseBodyMessageBatch
+	_, err = c.do(req, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListMessageBatches lists one page of the message batches. The most recently created batches are listed first.
+func (c *Client) ListMessageBatches(ctx context.Context, params RequestListParams) (*ResponseBodyMessageBatchesList, error) {
+	ctx = withRequestInfo(ctx, RequestInfo{Operation: OperationListMessageBatches})
+	req, err := c.newRequest(ctx, "GET", batchesEndpoint+params.encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result ResponseBodyMessageBatchesList
+	_, err = c.do(req, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CancelMessageBatch initiates the cancellation of a batch. The batch is "canceling" until
+// the requests in progress have finished.
+func (c *Client) CancelMessageBatch(ctx context.Context, batchId string) (*ResponseBodyMessageBatch, error) {
+	ctx = withRequestInfo(ctx, RequestInfo{Operation: OperationCancelMessageBatch})
+	req, err := c.newRequest(ctx, "POST", batchesEndpoint+"/"+url.PathEscape(batchId)+"/cancel", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result ResponseBodyMessageBatch
+	_, err = c.do(req, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeleteMessageBatch deletes a batch. Batches in progress must be canceled first.
+func (c *Client) DeleteMessageBatch(ctx context.Context, batchId string) (*ResponseBodyMessageBatchDeleted, error) {
+	ctx = withRequestInfo(ctx, RequestInfo{Operation: OperationDeleteMessageBatch})
+	req, err := c.newRequest(ctx, "DELETE", batchesEndpoint+"/"+url.PathEscape(batchId), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result ResponseBodyMessageBatchDeleted
+	_, err = c.do(req, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+type MessageBatchesIterator struct {
+	client *Client
+	params RequestListParams
+	page   []ResponseBodyMessageBatch
+	done   bool
+}
+
+// ListMessageBatchesIterator returns an iterator over all message batches, starting at the page of params.
+// Pages are fetched on demand.
+func (c *Client) ListMessageBatchesIterator(params RequestListParams) *MessageBatchesIterator {
+	return &MessageBatchesIterator{
+		client: c,
+		params: params,
+	}
+}
+
+// Next returns the next message batch, or io.EOF when there are no more batches.
+func (it *MessageBatchesIterator) Next(ctx context.Context) (ResponseBodyMessageBatch, error) {
+	for len(it.page) == 0 {
+		if it.done {
+			return ResponseBodyMessageBatch{}, io.EOF
+		}
+		res, err := it.client.ListMessageBatches(ctx, it.params)
+		if err != nil {
+			return ResponseBodyMessageBatch{}, err
+		}
+		it.page = res.Data
+		it.params = it.params.next(res.FirstId, res.LastId)
+		it.done = !res.HasMore
+	}
+	b := it.page[0]
+	it.page = it.page[1:]
+	return b, nil
+}
+
+func parseBatchBodyJSON(body RequestBodyMessageBatch) ([]byte, error) {
+	body.Requests = append([]RequestBodyMessageBatchRequest{}, body.Requests...)
+	for i, r := range body.Requests {
+		raw, err := parseBodyJSON(r.Params)
+		if err != nil {
+			return nil, err
+		}
+		body.Requests[i].ParamsRaw = json.RawMessage(raw)
+	}
+	return json.Marshal(body)
+}
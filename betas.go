@@ -0,0 +1,119 @@
+package v1
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Beta features, enabled with the anthropic-beta header.
+const (
+	BetaMessageBatches      = "message-batches-2024-09-24"
+	BetaPromptCaching       = "prompt-caching-2024-07-31"
+	BetaPDFs                = "pdfs-2024-09-25"
+	BetaTokenCounting       = "token-counting-2024-11-01"
+	BetaComputerUse20241022 = "computer-use-2024-10-22"
+	BetaComputerUse20250124 = "computer-use-2025-01-24"
+	BetaTokenEfficientTools = "token-efficient-tools-2025-02-19"
+	BetaOutput128k          = "output-128k-2025-02-19"
+	BetaMCPClient           = "mcp-client-2025-04-04"
+	BetaExtendedCacheTTL    = "extended-cache-ttl-2025-04-11"
+	BetaFilesAPI            = "files-api-2025-04-14"
+	BetaInterleavedThinking = "interleaved-thinking-2025-05-14"
+	BetaCodeExecution       = "code-execution-2025-05-22"
+	BetaContext1M           = "context-1m-2025-08-07"
+)
+
+const betaHeader = "Anthropic-Beta"
+
+// mergeBetas returns the betas in order without duplicates.
+// Each value may be a comma separated list, like the anthropic-beta header.
+func mergeBetas(betas ...string) []string {
+	var merged []string
+	seen := map[string]bool{}
+	for _, v := range betas {
+		for _, b := range strings.Split(v, ",") {
+			b = strings.TrimSpace(b)
+			if b == "" || seen[b] {
+				continue
+			}
+			seen[b] = true
+			merged = append(merged, b)
+		}
+	}
+	return merged
+}
+
+// addBetas merges the betas into the anthropic-beta header of the request.
+func addBetas(req *http.Request, betas ...string) {
+	merged := mergeBetas(append(req.Header.Values(betaHeader), betas...)...)
+	if len(merged) == 0 {
+		return
+	}
+	req.Header.Set(betaHeader, strings.Join(merged, ","))
+}
+
+// requiredBetas returns the betas needed by the features used in the request.
+func requiredBetas(body RequestBodyMessages) []string {
+	var f betaFeatures
+	for _, s := range body.SystemTypeText {
+		f.cacheControl(s.CacheControl)
+	}
+	for _, t := range body.Tools {
+		f.cacheControl(t.CacheControl)
+	}
+	for _, m := range body.Messages {
+		if m.Content != "" {
+			continue
+		}
+		f.blocks(m.Blocks())
+	}
+
+	var betas []string
+	if body.Thinking != nil && body.Thinking.Interleaved {
+		betas = append(betas, BetaInterleavedThinking)
+	}
+	if f.extendedCacheTTL {
+		betas = append(betas, BetaExtendedCacheTTL)
+	}
+	if f.files {
+		betas = append(betas, BetaFilesAPI)
+	}
+	return betas
+}
+
+// betaFeatures records the beta features found in the content of a request.
+type betaFeatures struct {
+	extendedCacheTTL bool
+	files            bool
+}
+
+func (f *betaFeatures) cacheControl(c *RequestCacheControl) {
+	if c != nil && c.Ttl == CacheControlTtl1h {
+		f.extendedCacheTTL = true
+	}
+}
+
+func (f *betaFeatures) blocks(blocks []ContentBlock) {
+	for _, b := range blocks {
+		switch b := b.(type) {
+		case RequestBodyMessagesMessagesContentTypeText:
+			f.cacheControl(b.CacheControl)
+		case RequestBodyMessagesMessagesContentTypeImage:
+			f.cacheControl(b.CacheControl)
+			if b.Source.Type == RequestBodyMessagesMessagesContentTypeImageSourceTypeFile {
+				f.files = true
+			}
+		case RequestBodyMessagesMessagesContentTypeDocument:
+			f.cacheControl(b.CacheControl)
+			if b.Source.Type == RequestBodyMessagesMessagesContentTypeDocumentSourceTypeFile {
+				f.files = true
+			}
+			f.blocks(b.Source.Content)
+		case RequestBodyMessagesMessagesContentTypeToolUse:
+			f.cacheControl(b.CacheControl)
+		case RequestBodyMessagesMessagesContentTypeToolResult:
+			f.cacheControl(b.CacheControl)
+			f.blocks(b.Blocks())
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package v1
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestRequiredBetas(t *testing.T) {
+	fileIdTool := RequestBodyMessagesTool{
+		Name:        "open_file",
+		InputSchema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{"file_id": map[string]interface{}{"type": "string"}}},
+	}
+	tests := []struct {
+		name string
+		body RequestBodyMessages
+		want []string
+	}{
+		{
+			name: "file_id in a tool schema and a tool_use input",
+			body: RequestBodyMessages{
+				Tools: []RequestBodyMessagesTool{fileIdTool},
+				Messages: []RequestBodyMessagesMessages{
+					{
+						Role: MessagesRoleAssistant,
+						ContentTypeToolUse: []RequestBodyMessagesMessagesContentTypeToolUse{
+							{Id: "toolu_1", Name: "open_file", Input: json.RawMessage(`{"file_id":"file_1","ttl":"1h"}`)},
+						},
+					},
+				},
+			},
+			want: nil,
+		},
+		{
+			name: "file sources",
+			body: RequestBodyMessages{
+				Messages: []RequestBodyMessagesMessages{
+					{
+						Role: MessagesRoleUser,
+						ContentTypeDocument: []RequestBodyMessagesMessagesContentTypeDocument{
+							{Source: TypeDocumentSourceLoadFileId("file_1")},
+						},
+					},
+				},
+			},
+			want: []string{BetaFilesAPI},
+		},
+		{
+			name: "1h cache in a tool result and interleaved thinking",
+			body: RequestBodyMessages{
+				Thinking: UseInterleavedThinking(1024),
+				Messages: []RequestBodyMessagesMessages{
+					{
+						Role: MessagesRoleUser,
+						ContentTypeToolResult: []RequestBodyMessagesMessagesContentTypeToolResult{
+							{
+								ToolUseId: "toolu_1",
+								ContentBlocks: []ContentBlock{
+									RequestBodyMessagesMessagesContentTypeImage{Source: TypeImageSourceLoadFileId("file_2")},
+								},
+								CacheControl: UseCacheEphemeral1h(),
+							},
+						},
+					},
+				},
+			},
+			want: []string{BetaInterleavedThinking, BetaExtendedCacheTTL, BetaFilesAPI},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := requiredBetas(tt.body); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("requiredBetas() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeBetas(t *testing.T) {
+	got := mergeBetas("a, b", "", "b,c", "a")
+	want := []string{"a", "b", "c"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeBetas() = %v, want %v", got, want)
+	}
+}
@@ -11,6 +11,15 @@ func UseCacheEphemeral() *RequestCacheControl {
 	return &t
 }
 
+// UseCacheEphemeral1h caches for 1 hour instead of 5 minutes.
+func UseCacheEphemeral1h() *RequestCacheControl {
+	t := RequestCacheControl{
+		Type: "ephemeral",
+		Ttl:  CacheControlTtl1h,
+	}
+	return &t
+}
+
 func UseSystemNoCache(text string) RequestBodySystemTypeText {
 	return RequestBodySystemTypeText{
 		Type:         "text",
@@ -0,0 +1,122 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+func (c *Client) newRequest(ctx context.Context, method string, path string, body []byte) (*http.Request, error) {
+	reqURL := c.config.BaseURL + path
+	reqHeaders := map[string]string{
+		"X-Api-Key":         c.config.ApiKey,
+		"Anthropic-Version": c.config.Version,
+		"Content-Type":      contentType,
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewBuffer(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	for k, vs := range c.config.Header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	for k, v := range reqHeaders {
+		req.Header.Set(k, v)
+	}
+	addBetas(req, append([]string{c.config.Beta}, c.config.Betas...)...)
+	return req, nil
+}
+
+// do sends the request and decodes a successful response into result.
+func (c *Client) do(req *http.Request, result interface{}) (ResponseMetadata, error) {
+	info, _ := RequestInfoFromContext(req.Context())
+	ctx, span := c.startSpan(req.Context(), info)
+	req = req.WithContext(ctx)
+
+	start := time.Now()
+	resp, err := c.send(req)
+	if err != nil {
+		c.logRequest(req.Context(), req, nil, ResponseMetadata{}, nil, err)
+		endSpan(span, ResponseMetadata{}, 0, err)
+		return ResponseMetadata{}, err
+	}
+
+	defer resp.Body.Close()
+
+	metadata := newResponseMetadata(resp.Header, time.Since(start))
+	if resp.StatusCode == http.StatusOK {
+		err = json.NewDecoder(resp.Body).Decode(result)
+	} else {
+		err = responseError(resp)
+	}
+
+	var usage *ResponseBodyMessagesUsage
+	if err == nil {
+		switch r := result.(type) {
+		case *ResponseBodyMessages:
+			usage = &r.Usage
+			setSpanResponse(span, r.Id, r.Model, r.StopReason, r.Usage)
+		case *ResponseBodyCountTokens:
+			span.SetAttributes(attrGenAIUsageInputTokens.Int64(r.InputTokens))
+		}
+	}
+	c.logRequest(req.Context(), req, resp, metadata, usage, err)
+	endSpan(span, metadata, resp.StatusCode, err)
+	return metadata, err
+}
+
+// doHTTP sends the request with the HTTPClient. The Timeout of the config applies until the
+// response headers are received; the body can be read for as long as it takes.
+func (c *Client) doHTTP(req *http.Request) (*http.Response, error) {
+	timeout := c.config.Timeout
+	if timeout <= 0 {
+		return c.config.HTTPClient.Do(req)
+	}
+
+	ctx, cancel := context.WithCancel(req.Context())
+	timer := time.AfterFunc(timeout, cancel)
+	resp, err := c.config.HTTPClient.Do(req.WithContext(ctx))
+	if !timer.Stop() {
+		if err == nil {
+			resp.Body.Close()
+		}
+		cancel()
+		if req.Context().Err() != nil {
+			return nil, req.Context().Err()
+		}
+		return nil, fmt.Errorf("timeout: no response headers within %s", timeout)
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelBody releases the context of the request when the body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+func responseError(resp *http.Response) error {
+	return newAPIError(resp)
+}
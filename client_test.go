@@ -0,0 +1,68 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a client that sends its requests to the handler, with short retry delays.
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...Option) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	c := NewClient("test-key", append([]Option{WithBaseURL(srv.URL)}, opts...)...)
+	c.config.RetryBaseDelay = time.Millisecond
+	c.config.RetryMaxDelay = 10 * time.Millisecond
+	return c
+}
+
+// writeSSE writes the events as a text/event-stream response. Each event is a type and its data.
+func writeSSE(w http.ResponseWriter, events ...[2]string) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	var b strings.Builder
+	for _, e := range events {
+		fmt.Fprintf(&b, "event: %s\ndata: %s\n\n", e[0], e[1])
+	}
+	w.Write([]byte(b.String()))
+}
+
+func TestClientTimeout(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Slow-Headers") != "" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("event: message_start\ndata: {\"message\":{\"id\":\"msg_1\"}}\n\n"))
+		w.(http.Flusher).Flush()
+		// the body takes longer than the timeout
+		time.Sleep(200 * time.Millisecond)
+		w.Write([]byte("event: message_stop\ndata: {}\n\n"))
+	}, WithTimeout(100*time.Millisecond))
+	c.config.MaxRetries = 0
+
+	stream, err := c.CreateMessagesStream(context.Background(), RequestBodyMessages{Model: "m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+	for {
+		_, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("stream was cut off: %v", err)
+		}
+	}
+
+	_, err = c.CreateMessages(context.Background(), RequestBodyMessages{Model: "m"}, WithRequestHeader("X-Slow-Headers", "1"))
+	if err == nil || !strings.Contains(err.Error(), "no response headers within 100ms") {
+		t.Errorf("err = %v, want a response headers timeout", err)
+	}
+}
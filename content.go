@@ -0,0 +1,164 @@
+package v1
+
+import "encoding/json"
+
+// ContentBlock is a single block of message content.
+// Any type that marshals to a content block object can be used, so block kinds
+// that this package does not know about yet can be sent as well.
+type ContentBlock interface {
+	ContentBlockType() string
+}
+
+func (c RequestBodyMessagesMessagesContentTypeText) ContentBlockType() string {
+	return RequestBodyMessagesMessagesContentTypeTextType
+}
+
+func (c RequestBodyMessagesMessagesContentTypeText) MarshalJSON() ([]byte, error) {
+	type alias RequestBodyMessagesMessagesContentTypeText
+	c.Type = c.ContentBlockType()
+	return json.Marshal(alias(c))
+}
+
+func (c RequestBodyMessagesMessagesContentTypeImage) ContentBlockType() string {
+	return RequestBodyMessagesMessagesContentTypeImageType
+}
+
+func (c RequestBodyMessagesMessagesContentTypeImage) MarshalJSON() ([]byte, error) {
+	type alias RequestBodyMessagesMessagesContentTypeImage
+	c.Type = c.ContentBlockType()
+	return json.Marshal(alias(c))
+}
+
+func (c RequestBodyMessagesMessagesContentTypeDocument) ContentBlockType() string {
+	return RequestBodyMessagesMessagesContentTypeDocumentType
+}
+
+func (c RequestBodyMessagesMessagesContentTypeDocument) MarshalJSON() ([]byte, error) {
+	type alias RequestBodyMessagesMessagesContentTypeDocument
+	c.Type = c.ContentBlockType()
+	return json.Marshal(alias(c))
+}
+
+func (c RequestBodyMessagesMessagesContentTypeToolUse) ContentBlockType() string {
+	return RequestBodyMessagesMessagesContentTypeToolUseType
+}
+
+func (c RequestBodyMessagesMessagesContentTypeToolUse) MarshalJSON() ([]byte, error) {
+	type alias RequestBodyMessagesMessagesContentTypeToolUse
+	c.Type = c.ContentBlockType()
+	if len(c.Input) == 0 {
+		c.Input = json.RawMessage("{}")
+	}
+	return json.Marshal(alias(c))
+}
+
+func (c RequestBodyMessagesMessagesContentTypeToolResult) ContentBlockType() string {
+	return RequestBodyMessagesMessagesContentTypeToolResultType
+}
+
+func (c RequestBodyMessagesMessagesContentTypeToolResult) MarshalJSON() ([]byte, error) {
+	type alias RequestBodyMessagesMessagesContentTypeToolResult
+	c.Type = c.ContentBlockType()
+	if c.Content != "" {
+		c.ContentRaw = c.Content
+	} else if blocks := c.Blocks(); len(blocks) > 0 {
+		c.ContentRaw = blocks
+	}
+	return json.Marshal(alias(c))
+}
+
+// Blocks returns ContentBlocks followed by the ContentTypeText and ContentTypeImage blocks.
+func (c RequestBodyMessagesMessagesContentTypeToolResult) Blocks() []ContentBlock {
+	blocks := append([]ContentBlock{}, c.ContentBlocks...)
+	for _, v := range c.ContentTypeText {
+		blocks = append(blocks, v)
+	}
+	for _, v := range c.ContentTypeImage {
+		blocks = append(blocks, v)
+	}
+	return blocks
+}
+
+func (c RequestBodyMessagesMessagesContentTypeThinking) ContentBlockType() string {
+	return RequestBodyMessagesMessagesContentTypeThinkingType
+}
+
+func (c RequestBodyMessagesMessagesContentTypeThinking) MarshalJSON() ([]byte, error) {
+	type alias RequestBodyMessagesMessagesContentTypeThinking
+	c.Type = c.ContentBlockType()
+	return json.Marshal(alias(c))
+}
+
+func (c RequestBodyMessagesMessagesContentTypeRedactedThinking) ContentBlockType() string {
+	return RequestBodyMessagesMessagesContentTypeRedactedThinkingType
+}
+
+func (c RequestBodyMessagesMessagesContentTypeRedactedThinking) MarshalJSON() ([]byte, error) {
+	type alias RequestBodyMessagesMessagesContentTypeRedactedThinking
+	c.Type = c.ContentBlockType()
+	return json.Marshal(alias(c))
+}
+
+// Blocks returns the content of the message as an ordered list of blocks.
+// ContentBlocks come first, followed by the ContentTypeToolResult, ContentTypeDocument,
+// ContentTypeText, ContentTypeImage and ContentTypeToolUse blocks.
+func (m RequestBodyMessagesMessages) Blocks() []ContentBlock {
+	blocks := append([]ContentBlock{}, m.ContentBlocks...)
+	for _, v := range m.ContentTypeToolResult {
+		blocks = append(blocks, v)
+	}
+	for _, v := range m.ContentTypeDocument {
+		blocks = append(blocks, v)
+	}
+	for _, v := range m.ContentTypeText {
+		blocks = append(blocks, v)
+	}
+	for _, v := range m.ContentTypeImage {
+		blocks = append(blocks, v)
+	}
+	for _, v := range m.ContentTypeToolUse {
+		blocks = append(blocks, v)
+	}
+	return blocks
+}
+
+// ContentBlock converts a response block so it can be sent back in an assistant message.
+// It returns nil for block types that cannot be sent back.
+func (c ResponseBodyMessagesContent) ContentBlock() ContentBlock {
+	switch c.Type {
+	case ResponseBodyMessagesContentTypeText:
+		return RequestBodyMessagesMessagesContentTypeText{
+			Type:      RequestBodyMessagesMessagesContentTypeTextType,
+			Text:      c.Text,
+			Citations: c.Citations,
+		}
+	case ResponseBodyMessagesContentTypeThinking:
+		return RequestBodyMessagesMessagesContentTypeThinking{
+			Type:      RequestBodyMessagesMessagesContentTypeThinkingType,
+			Thinking:  c.Thinking,
+			Signature: c.Signature,
+		}
+	case ResponseBodyMessagesContentTypeRedactedThinking:
+		return RequestBodyMessagesMessagesContentTypeRedactedThinking{
+			Type: RequestBodyMessagesMessagesContentTypeRedactedThinkingType,
+			Data: c.Data,
+		}
+	case ResponseBodyMessagesContentTypeToolUse:
+		return c.ToolUse()
+	}
+	return nil
+}
+
+// Message converts the response into an assistant message, so it can be appended to
+// the conversation of the next request.
+func (r *ResponseBodyMessages) Message() RequestBodyMessagesMessages {
+	m := RequestBodyMessagesMessages{
+		Role: MessagesRoleAssistant,
+	}
+	for _, c := range r.Content {
+		if b := c.ContentBlock(); b != nil {
+			m.ContentBlocks = append(m.ContentBlocks, b)
+		}
+	}
+	return m
+}
@@ -0,0 +1,57 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+)
+
+const countTokensEndpoint = "v1/messages/count_tokens"
+
+// count_tokens accepts only these fields of a messages request
+var countTokensFields = []string{"model", "messages", "system", "tools", "tool_choice", "thinking"}
+
+type ResponseBodyCountTokens struct {
+	InputTokens int64 `json:"input_tokens"`
+}
+
+// CountTokens counts the input tokens of a messages request without creating a message.
+func (c *Client) CountTokens(ctx context.Context, body RequestBodyMessages) (*ResponseBodyCountTokens, error) {
+	jsonBody, err := parseCountTokensBodyJSON(body)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = withRequestInfo(ctx, RequestInfo{Operation: OperationCountTokens, Model: body.Model, body: &body})
+	req, err := c.newRequest(ctx, "POST", countTokensEndpoint, jsonBody)
+	if err != nil {
+		return nil, err
+	}
+	addBetas(req, requiredBetas(body)...)
+
+	var result ResponseBodyCountTokens
+	_, err = c.do(req, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func parseCountTokensBodyJSON(body RequestBodyMessages) ([]byte, error) {
+	jsonBody, err := parseBodyJSON(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	err = json.Unmarshal(jsonBody, &fields)
+	if err != nil {
+		return nil, err
+	}
+	countFields := map[string]json.RawMessage{}
+	for _, k := range countTokensFields {
+		if v, ok := fields[k]; ok {
+			countFields[k] = v
+		}
+	}
+	return json.Marshal(countFields)
+}
@@ -0,0 +1,76 @@
+package v1
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func TypeDocumentSourceLoadBase64(mediaType string, data string) RequestBodyMessagesMessagesContentTypeDocumentSource {
+	return RequestBodyMessagesMessagesContentTypeDocumentSource{
+		Type:      RequestBodyMessagesMessagesContentTypeDocumentSourceTypeBase64,
+		MediaType: mediaType,
+		Data:      data,
+	}
+}
+
+func TypeDocumentSourceLoadText(text string) RequestBodyMessagesMessagesContentTypeDocumentSource {
+	return RequestBodyMessagesMessagesContentTypeDocumentSource{
+		Type:      RequestBodyMessagesMessagesContentTypeDocumentSourceTypeText,
+		MediaType: "text/plain",
+		Data:      text,
+	}
+}
+
+func TypeDocumentSourceLoadUrl(url string) RequestBodyMessagesMessagesContentTypeDocumentSource {
+	return RequestBodyMessagesMessagesContentTypeDocumentSource{
+		Type: RequestBodyMessagesMessagesContentTypeDocumentSourceTypeUrl,
+		Url:  url,
+	}
+}
+
+// TypeDocumentSourceLoadContent uses the blocks as a custom content document.
+// Each block is a chunk that can be cited on its own.
+func TypeDocumentSourceLoadContent(blocks ...ContentBlock) RequestBodyMessagesMessagesContentTypeDocumentSource {
+	return RequestBodyMessagesMessagesContentTypeDocumentSource{
+		Type:    RequestBodyMessagesMessagesContentTypeDocumentSourceTypeContent,
+		Content: blocks,
+	}
+}
+
+// TypeDocumentSourceLoadFileId uses a file uploaded with the Files API.
+func TypeDocumentSourceLoadFileId(fileId string) RequestBodyMessagesMessagesContentTypeDocumentSource {
+	return RequestBodyMessagesMessagesContentTypeDocumentSource{
+		Type:   RequestBodyMessagesMessagesContentTypeDocumentSourceTypeFile,
+		FileId: fileId,
+	}
+}
+
+// TypeDocumentSourceLoadFile loads a .pdf file as a base64 source, or a .txt or .md file as a plain text source.
+// Other extensions return an error.
+func TypeDocumentSourceLoadFile(filePath string) (RequestBodyMessagesMessagesContentTypeDocumentSource, error) {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	if ext != ".pdf" && ext != ".txt" && ext != ".md" {
+		return RequestBodyMessagesMessagesContentTypeDocumentSource{}, fmt.Errorf("unsupported document file extension %q, want .pdf, .txt or .md", ext)
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return RequestBodyMessagesMessagesContentTypeDocumentSource{}, err
+	}
+
+	if ext == ".pdf" {
+		b64 := base64.StdEncoding.EncodeToString(data)
+		return TypeDocumentSourceLoadBase64("application/pdf", b64), nil
+	}
+	return TypeDocumentSourceLoadText(string(data)), nil
+}
+
+func UseCitations() *RequestCitations {
+	t := RequestCitations{
+		Enabled: true,
+	}
+	return &t
+}
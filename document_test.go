@@ -0,0 +1,45 @@
+package v1
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTypeDocumentSourceLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, data string) string {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	tests := []struct {
+		path      string
+		wantType  string
+		mediaType string
+		data      string
+	}{
+		{write("report.PDF", "%PDF-1.4"), RequestBodyMessagesMessagesContentTypeDocumentSourceTypeBase64, "application/pdf", "JVBERi0xLjQ="},
+		{write("notes.txt", "hello"), RequestBodyMessagesMessagesContentTypeDocumentSourceTypeText, "text/plain", "hello"},
+		{write("README.md", "# title"), RequestBodyMessagesMessagesContentTypeDocumentSourceTypeText, "text/plain", "# title"},
+	}
+	for _, tt := range tests {
+		source, err := TypeDocumentSourceLoadFile(tt.path)
+		if err != nil {
+			t.Errorf("%s: %v", tt.path, err)
+			continue
+		}
+		if source.Type != tt.wantType || source.MediaType != tt.mediaType || source.Data != tt.data {
+			t.Errorf("%s = %+v, want %s %s %q", tt.path, source, tt.wantType, tt.mediaType, tt.data)
+		}
+	}
+
+	for _, name := range []string{"sheet.docx", "image.png", "noext"} {
+		if _, err := TypeDocumentSourceLoadFile(write(name, "data")); err == nil {
+			t.Errorf("%s: want an unsupported extension error", name)
+		}
+	}
+}
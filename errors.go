@@ -0,0 +1,114 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const (
+	ErrorTypeInvalidRequest  = "invalid_request_error"
+	ErrorTypeAuthentication  = "authentication_error"
+	ErrorTypePermission      = "permission_error"
+	ErrorTypeNotFound        = "not_found_error"
+	ErrorTypeRequestTooLarge = "request_too_large"
+	ErrorTypeRateLimit       = "rate_limit_error"
+	ErrorTypeAPI             = "api_error"
+	ErrorTypeOverloaded      = "overloaded_error"
+)
+
+const StatusOverloaded = 529
+
+// status codes of the error types, for errors that arrive as stream events
+var errorTypeStatusCodes = map[string]int{
+	ErrorTypeInvalidRequest:  http.StatusBadRequest,
+	ErrorTypeAuthentication:  http.StatusUnauthorized,
+	ErrorTypePermission:      http.StatusForbidden,
+	ErrorTypeNotFound:        http.StatusNotFound,
+	ErrorTypeRequestTooLarge: http.StatusRequestEntityTooLarge,
+	ErrorTypeRateLimit:       http.StatusTooManyRequests,
+	ErrorTypeAPI:             http.StatusInternalServerError,
+	ErrorTypeOverloaded:      StatusOverloaded,
+}
+
+// APIError is returned for error responses of the API and for stream error events.
+//
+//	var apiErr *claude.APIError
+//	if errors.As(err, &apiErr) && apiErr.IsRateLimited() {
+//		...
+//	}
+type APIError struct {
+	StatusCode int
+	Type       string // "invalid_request_error", "rate_limit_error", "overloaded_error", ...
+	Message    string
+	RequestID  string // request-id header
+	Body       []byte // raw response body or event data
+}
+
+func (e *APIError) Error() string {
+	if e.StatusCode == 0 {
+		return fmt.Sprintf("%s: %s", e.Type, e.Message)
+	}
+	status := http.StatusText(e.StatusCode)
+	if e.StatusCode == StatusOverloaded {
+		status = "Overloaded"
+	}
+	return fmt.Sprintf("%d %s: %s", e.StatusCode, status, e.Message)
+}
+
+func (e *APIError) IsRateLimited() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.Type == ErrorTypeRateLimit
+}
+
+func (e *APIError) IsOverloaded() bool {
+	return e.StatusCode == StatusOverloaded || e.Type == ErrorTypeOverloaded
+}
+
+// IsRetryable reports whether the same request may succeed when it is sent again:
+// timeouts, conflicts, rate limits, overloads and server errors.
+func (e *APIError) IsRetryable() bool {
+	switch e.StatusCode {
+	case http.StatusRequestTimeout, http.StatusConflict, http.StatusTooManyRequests:
+		return true
+	}
+	return e.StatusCode >= 500 || e.Type == ErrorTypeRateLimit || e.Type == ErrorTypeAPI || e.Type == ErrorTypeOverloaded
+}
+
+// newAPIError reads the error response. The body is closed by the caller.
+func newAPIError(resp *http.Response) *APIError {
+	body, _ := io.ReadAll(resp.Body)
+	e := &APIError{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("Request-Id"),
+		Body:       body,
+	}
+
+	var result ResponseError
+	if err := json.Unmarshal(body, &result); err == nil && result.Error.Message != "" {
+		e.Type = result.Error.Type
+		e.Message = result.Error.Message
+		return e
+	}
+	e.Message = string(bytes.TrimSpace(body))
+	if e.Message == "" {
+		e.Message = http.StatusText(resp.StatusCode)
+	}
+	return e
+}
+
+// newAPIErrorFromEvent decodes the data of a stream error event.
+func newAPIErrorFromEvent(data []byte) (*APIError, error) {
+	var result ResponseError
+	err := json.Unmarshal(data, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &APIError{
+		StatusCode: errorTypeStatusCodes[result.Error.Type],
+		Type:       result.Error.Type,
+		Message:    result.Error.Message,
+		Body:       data,
+	}, nil
+}
@@ -0,0 +1,13 @@
+module github.com/potproject/claude-sdk-go/example/count_tokens
+
+go 1.21
+
+require github.com/potproject/claude-sdk-go v1.1.0
+
+require (
+	github.com/tmaxmax/go-sse v0.8.0 // indirect
+	go.opentelemetry.io/otel v1.24.0 // indirect
+	go.opentelemetry.io/otel/trace v1.24.0 // indirect
+)
+
+replace github.com/potproject/claude-sdk-go => ../../
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	claude "github.com/potproject/claude-sdk-go"
+)
+
+func main() {
+	apiKey := os.Getenv("API_KEY")
+	c := claude.NewClient(apiKey)
+	m := claude.RequestBodyMessages{
+		Model:  "claude-3-7-sonnet-20250219",
+		System: "You are a scientist",
+		Messages: []claude.RequestBodyMessagesMessages{
+			{
+				Role:    claude.MessagesRoleUser,
+				Content: "Hello, Claude",
+			},
+		},
+	}
+	ctx := context.Background()
+	res, err := c.CountTokens(ctx, m)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(res.InputTokens)
+	// Output:
+	// 14
+}
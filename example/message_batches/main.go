@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	claude "github.com/potproject/claude-sdk-go"
+)
+
+func main() {
+	apiKey := os.Getenv("API_KEY")
+	c := claude.NewClient(apiKey)
+	b := claude.RequestBodyMessageBatch{
+		Requests: []claude.RequestBodyMessageBatchRequest{
+			{
+				CustomId: "my-first-request",
+				Params: claude.RequestBodyMessages{
+					Model:     "claude-3-7-sonnet-20250219",
+					MaxTokens: 1024,
+					Messages: []claude.RequestBodyMessagesMessages{
+						{
+							Role:    claude.MessagesRoleUser,
+							Content: "Hello, world",
+						},
+					},
+				},
+			},
+			{
+				CustomId: "my-second-request",
+				Params: claude.RequestBodyMessages{
+					Model:     "claude-3-7-sonnet-20250219",
+					MaxTokens: 1024,
+					Messages: []claude.RequestBodyMessagesMessages{
+						{
+							Role:    claude.MessagesRoleUser,
+							Content: "Hi again, friend",
+						},
+					},
+				},
+			},
+		},
+	}
+	ctx := context.Background()
+	batch, err := c.CreateMessageBatch(ctx, b)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(batch.Id, batch.ProcessingStatus)
+
+	// Poll until the batch has ended
+	batch, err = c.WaitForBatch(ctx, batch.Id, claude.WaitForBatchOptions{
+		InitialInterval: 10 * time.Second,
+		MaxInterval:     5 * time.Minute,
+		OnProgress: func(b claude.ResponseBodyMessageBatch) {
+			fmt.Println(b.ProcessingStatus, b.RequestCounts.Processing, b.RequestCounts.Succeeded, b.RequestCounts.Errored)
+		},
+		CancelOnDone: true, // cancel the batch when ctx is done
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	r, err := c.GetMessageBatchResults(ctx, batch.Id, 0)
+	if err != nil {
+		panic(err)
+	}
+	defer r.Close()
+	for {
+		res, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			panic(err)
+		}
+		if res.Result.Type == claude.MessageBatchResultTypeSucceeded {
+			fmt.Println(res.CustomId, res.Result.Message.Content[0].Text)
+		} else {
+			fmt.Println(res.CustomId, res.Result.Type)
+		}
+	}
+}
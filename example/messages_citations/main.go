@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	claude "github.com/potproject/claude-sdk-go"
+)
+
+func main() {
+	apiKey := os.Getenv("API_KEY")
+	c := claude.NewClient(apiKey)
+	m := claude.RequestBodyMessages{
+		Model:     "claude-3-7-sonnet-20250219",
+		MaxTokens: 1024,
+		Messages: []claude.RequestBodyMessagesMessages{
+			{
+				Role: claude.MessagesRoleUser,
+				ContentTypeDocument: []claude.RequestBodyMessagesMessagesContentTypeDocument{
+					{
+						Source:    claude.TypeDocumentSourceLoadText("The grass is green. The sky is blue."),
+						Title:     "My Document",
+						Citations: claude.UseCitations(),
+					},
+				},
+				ContentTypeText: []claude.RequestBodyMessagesMessagesContentTypeText{
+					{
+						Text: "What color is the grass and sky?",
+					},
+				},
+			},
+		},
+	}
+	ctx := context.Background()
+	res, err := c.CreateMessages(ctx, m)
+	if err != nil {
+		panic(err)
+	}
+	for _, v := range res.Content {
+		fmt.Print(v.Text)
+		for _, citation := range v.Citations {
+			if citation.Type == claude.ResponseBodyMessagesCitationTypeCharLocation {
+				fmt.Printf(" [%s: %q (%d-%d)]", citation.DocumentTitle, citation.CitedText, citation.StartCharIndex, citation.EndCharIndex)
+			}
+		}
+	}
+	fmt.Println()
+}
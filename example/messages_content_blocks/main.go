@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	claude "github.com/potproject/claude-sdk-go"
+)
+
+func main() {
+	apiKey := os.Getenv("API_KEY")
+	c := claude.NewClient(apiKey)
+	m := claude.RequestBodyMessages{
+		Model:     "claude-3-7-sonnet-20250219",
+		MaxTokens: 1024,
+		Messages: []claude.RequestBodyMessagesMessages{
+			{
+				Role: claude.MessagesRoleUser,
+				// ContentBlocks are sent in order
+				ContentBlocks: []claude.ContentBlock{
+					claude.RequestBodyMessagesMessagesContentTypeText{
+						Text: "Image 1:",
+					},
+					claude.RequestBodyMessagesMessagesContentTypeImage{
+						Source: claude.TypeImageSourceLoadUrl("https://upload.wikimedia.org/wikipedia/commons/a/a7/Camponotus_flavomarginatus_ant.jpg"),
+					},
+					claude.RequestBodyMessagesMessagesContentTypeText{
+						Text: "Image 2:",
+					},
+					claude.RequestBodyMessagesMessagesContentTypeImage{
+						Source: claude.TypeImageSourceLoadUrl("https://upload.wikimedia.org/wikipedia/commons/b/b5/Iridescent.green.sweat.bee1.jpg"),
+					},
+					claude.RequestBodyMessagesMessagesContentTypeText{
+						Text: "How are these images different?",
+					},
+				},
+			},
+		},
+	}
+	ctx := context.Background()
+	res, err := c.CreateMessages(ctx, m)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(res.Content[0].Text)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	claude "github.com/potproject/claude-sdk-go"
+)
+
+func main() {
+	apiKey := os.Getenv("API_KEY")
+	c := claude.NewClient(apiKey)
+	// TypeDocumentSourceLoadFile("report.pdf") and TypeDocumentSourceLoadBase64("application/pdf", "JVBERi0x...") are also available
+	m := claude.RequestBodyMessages{
+		Model:     "claude-3-7-sonnet-20250219",
+		MaxTokens: 1024,
+		Messages: []claude.RequestBodyMessagesMessages{
+			{
+				Role: claude.MessagesRoleUser,
+				ContentTypeDocument: []claude.RequestBodyMessagesMessagesContentTypeDocument{
+					{
+						Source:       claude.TypeDocumentSourceLoadUrl("https://assets.anthropic.com/m/1cd9d098ac3e6467/original/Claude-3-Model-Card-October-Addendum.pdf"),
+						Title:        "Claude 3 Model Card October Addendum", // optional
+						CacheControl: claude.UseCacheEphemeral(),             // optional
+					},
+				},
+				ContentTypeText: []claude.RequestBodyMessagesMessagesContentTypeText{
+					{
+						Text: "What are the key findings in this document?",
+					},
+				},
+			},
+		},
+	}
+	ctx := context.Background()
+	res, err := c.CreateMessages(ctx, m)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(res.Content[0].Text)
+}
@@ -4,6 +4,10 @@ go 1.21
 
 require github.com/potproject/claude-sdk-go v1.0.1
 
-require github.com/tmaxmax/go-sse v0.8.0 // indirect
+require (
+	github.com/tmaxmax/go-sse v0.8.0 // indirect
+	go.opentelemetry.io/otel v1.24.0 // indirect
+	go.opentelemetry.io/otel/trace v1.24.0 // indirect
+)
 
 replace github.com/potproject/claude-sdk-go => ../../
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	claude "github.com/potproject/claude-sdk-go"
+)
+
+func main() {
+	apiKey := os.Getenv("API_KEY")
+	c := claude.NewClient(apiKey)
+	m := claude.RequestBodyMessages{
+		Model:     "claude-3-7-sonnet-20250219",
+		MaxTokens: 1024,
+		Tools: []claude.RequestBodyMessagesTool{
+			{
+				Name:        "get_weather",
+				Description: "Get the current weather in a given location",
+				InputSchema: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"location": map[string]interface{}{
+							"type":        "string",
+							"description": "The city and state, e.g. San Francisco, CA",
+						},
+					},
+					"required": []string{"location"},
+				},
+			},
+		},
+		Messages: []claude.RequestBodyMessagesMessages{
+			{
+				Role:    claude.MessagesRoleUser,
+				Content: "What is the weather like in San Francisco?",
+			},
+		},
+	}
+	ctx := context.Background()
+	res, err := c.CreateMessages(ctx, m)
+	if err != nil {
+		panic(err)
+	}
+	if res.StopReason != claude.ResponseBodyMessagesStopReasonToolUse {
+		fmt.Println(res.Content[0].Text)
+		return
+	}
+
+	// Send the tool results back to Claude
+	user := claude.RequestBodyMessagesMessages{
+		Role: claude.MessagesRoleUser,
+	}
+	for _, v := range res.ToolUses() {
+		fmt.Println(v.Name, string(v.Input))
+		user.ContentTypeToolResult = append(user.ContentTypeToolResult, claude.UseToolResult(v.Id, "15 degrees, sunny"))
+	}
+	m.Messages = append(m.Messages, res.Message(), user)
+	res, err = c.CreateMessages(ctx, m)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(res.Content[0].Text)
+	// Output:
+	// get_weather {"location":"San Francisco, CA"}
+	// The current weather in San Francisco is 15 degrees and sunny.
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	claude "github.com/potproject/claude-sdk-go"
+)
+
+type Contact struct {
+	Name  string `json:"name" jsonschema:"required"`
+	Email string `json:"email" jsonschema:"required"`
+	Phone string `json:"phone,omitempty"`
+}
+
+func main() {
+	apiKey := os.Getenv("API_KEY")
+	c := claude.NewClient(apiKey)
+	tool, err := claude.NewTool("record_contact", "Record the contact information", Contact{})
+	if err != nil {
+		panic(err)
+	}
+	m := claude.RequestBodyMessages{
+		Model:     "claude-3-7-sonnet-20250219",
+		MaxTokens: 1024,
+		Tools:     []claude.RequestBodyMessagesTool{tool},
+		// Force Claude to use the record_contact tool
+		ToolChoice: claude.UseToolChoiceTool("record_contact").DisableParallel(),
+		Messages: []claude.RequestBodyMessagesMessages{
+			{
+				Role:    claude.MessagesRoleUser,
+				Content: "Please contact John Smith at john.smith@example.com or 555-0100.",
+			},
+		},
+	}
+	ctx := context.Background()
+	res, err := c.CreateMessages(ctx, m)
+	if err != nil {
+		panic(err)
+	}
+	var contact Contact
+	if err := res.ToolUses()[0].UnmarshalInput(&contact); err != nil {
+		panic(err)
+	}
+	fmt.Printf("%+v\n", contact)
+	// Output:
+	// {Name:John Smith Email:john.smith@example.com Phone:555-0100}
+}
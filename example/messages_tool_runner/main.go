@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	claude "github.com/potproject/claude-sdk-go"
+)
+
+type GetWeatherInput struct {
+	Location string `json:"location" jsonschema:"required,description=The city and state\\, e.g. San Francisco\\, CA"`
+}
+
+func main() {
+	apiKey := os.Getenv("API_KEY")
+	c := claude.NewClient(apiKey)
+	tool, err := claude.NewTool("get_weather", "Get the current weather in a given location", GetWeatherInput{})
+	if err != nil {
+		panic(err)
+	}
+	runner := claude.NewToolRunner(c)
+	runner.MaxConcurrency = 4             // run parallel tool calls concurrently
+	runner.ToolTimeout = 10 * time.Second // a tool that times out is sent back as an is_error tool_result
+	runner.Register(tool, func(ctx context.Context, input json.RawMessage) (string, error) {
+		var in GetWeatherInput
+		if err := json.Unmarshal(input, &in); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("The weather in %s is 15 degrees, sunny", in.Location), nil
+	})
+
+	m := claude.RequestBodyMessages{
+		Model:     "claude-3-7-sonnet-20250219",
+		MaxTokens: 1024,
+		Messages: []claude.RequestBodyMessagesMessages{
+			{
+				Role:    claude.MessagesRoleUser,
+				Content: "What is the weather like in San Francisco?",
+			},
+		},
+	}
+	ctx := context.Background()
+	res, err := runner.Run(ctx, m)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(res.Response.Content[0].Text)
+	fmt.Println(res.Iterations, res.Usage.InputTokens, res.Usage.OutputTokens)
+}
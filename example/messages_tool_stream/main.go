@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	claude "github.com/potproject/claude-sdk-go"
+)
+
+type GetWeatherInput struct {
+	Location string `json:"location" jsonschema:"required,description=The city and state\\, e.g. San Francisco\\, CA"`
+}
+
+func main() {
+	apiKey := os.Getenv("API_KEY")
+	c := claude.NewClient(apiKey)
+	tool, err := claude.NewTool("get_weather", "Get the current weather in a given location", GetWeatherInput{})
+	if err != nil {
+		panic(err)
+	}
+	m := claude.RequestBodyMessages{
+		Model:     "claude-3-7-sonnet-20250219",
+		MaxTokens: 1024,
+		Tools:     []claude.RequestBodyMessagesTool{tool},
+		Messages: []claude.RequestBodyMessagesMessages{
+			{
+				Role:    claude.MessagesRoleUser,
+				Content: "What is the weather like in San Francisco?",
+			},
+		},
+	}
+	ctx := context.Background()
+	stream, err := c.CreateMessagesStream(ctx, m)
+	if err != nil {
+		panic(err)
+	}
+	defer stream.Close()
+	for {
+		res, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			panic(err)
+		}
+		content := res.Content[0]
+		if content.Type != claude.ResponseBodyMessagesContentTypeToolUse {
+			fmt.Printf("%s", content.Text)
+			continue
+		}
+		switch {
+		case content.Input != nil:
+			// the tool_use block is complete
+			fmt.Printf("\n[tool_use] %s %s\n", content.Name, string(content.Input))
+		case content.PartialJson != "":
+			fmt.Printf("%s", content.PartialJson)
+		default:
+			fmt.Printf("\n[tool_use start] %s %s\n", content.Id, content.Name)
+		}
+	}
+	fmt.Println()
+}
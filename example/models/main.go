@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	claude "github.com/potproject/claude-sdk-go"
+)
+
+func main() {
+	apiKey := os.Getenv("API_KEY")
+	c := claude.NewClient(apiKey)
+	ctx := context.Background()
+
+	// Pages are fetched on demand
+	it := c.ListModelsIterator(claude.RequestListParams{Limit: 20})
+	for {
+		model, err := it.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(model.Id, model.DisplayName, model.CreatedAt)
+	}
+
+	model, err := c.GetModel(ctx, "claude-3-7-sonnet-latest")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(model.Id, model.DisplayName)
+}
@@ -2,10 +2,12 @@ package v1_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	claude "github.com/potproject/claude-sdk-go"
 )
@@ -250,3 +252,64 @@ func Example_thinking_stream() {
 
 	// Would you like me to elaborate on any particular aspect of future population trends?
 }
+
+func ExampleNewTool() {
+	type GetWeatherInput struct {
+		Location string `json:"location" jsonschema:"required,description=The city and state\\, e.g. San Francisco\\, CA"`
+		Unit     string `json:"unit,omitempty" jsonschema:"enum=celsius,enum=fahrenheit"`
+		Days     int    `json:"days,omitempty" jsonschema:"minimum=1,maximum=7"`
+	}
+	tool, err := claude.NewTool("get_weather", "Get the current weather in a given location", GetWeatherInput{})
+	if err != nil {
+		panic(err)
+	}
+	schema, err := json.Marshal(tool.InputSchema)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(schema))
+
+	toolUse := claude.ResponseBodyMessagesContent{
+		Type:  claude.ResponseBodyMessagesContentTypeToolUse,
+		Name:  "get_weather",
+		Input: json.RawMessage(`{"location":"San Francisco, CA","unit":"celsius"}`),
+	}
+	var input GetWeatherInput
+	if err := toolUse.UnmarshalInput(&input); err != nil {
+		panic(err)
+	}
+	fmt.Println(input.Location, input.Unit)
+	// Output:
+	// {"type":"object","properties":{"days":{"type":"integer","minimum":1,"maximum":7},"location":{"type":"string","description":"The city and state, e.g. San Francisco, CA"},"unit":{"type":"string","enum":["celsius","fahrenheit"]}},"required":["location"]}
+	// San Francisco, CA celsius
+}
+
+func ExampleNewMessageBatchResultsReader() {
+	results := `{"custom_id":"my-first-request","result":{"type":"succeeded","message":{"id":"msg_01","type":"message","role":"assistant","content":[{"type":"text","text":"Hello! How can I assist you today?"}],"model":"claude-3-7-sonnet-20250219","stop_reason":"end_turn","usage":{"input_tokens":10,"output_tokens":12}}}}
+{"custom_id":"my-second-request","result":{"type":"errored","error":{"type":"error","error":{"type":"invalid_request_error","message":"max_tokens: Field required"}}}}
+{"custom_id":"my-third-request","result":{"type":"expired"}}
+`
+	r := claude.NewMessageBatchResultsReader(strings.NewReader(results), 0)
+	defer r.Close()
+	for {
+		res, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			panic(err)
+		}
+		switch res.Result.Type {
+		case claude.MessageBatchResultTypeSucceeded:
+			fmt.Println(res.CustomId, res.Result.Message.Content[0].Text)
+		case claude.MessageBatchResultTypeErrored:
+			fmt.Println(res.CustomId, res.Result.Error.Error.Type, res.Result.Error.Error.Message)
+		default:
+			fmt.Println(res.CustomId, res.Result.Type)
+		}
+	}
+	// Output:
+	// my-first-request Hello! How can I assist you today?
+	// my-second-request invalid_request_error max_tokens: Field required
+	// my-third-request expired
+}
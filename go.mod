@@ -2,4 +2,16 @@ module github.com/potproject/claude-sdk-go
 
 go 1.21
 
-require github.com/tmaxmax/go-sse v0.8.0 // indirect
+require (
+	github.com/tmaxmax/go-sse v0.8.0
+	go.opentelemetry.io/otel v1.24.0
+	go.opentelemetry.io/otel/sdk v1.24.0
+	go.opentelemetry.io/otel/trace v1.24.0
+)
+
+require (
+	github.com/go-logr/logr v1.4.1 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	go.opentelemetry.io/otel/metric v1.24.0 // indirect
+	golang.org/x/sys v0.17.0 // indirect
+)
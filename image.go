@@ -21,6 +21,14 @@ func TypeImageSourceLoadUrl(url string) RequestBodyMessagesMessagesContentTypeIm
 	}
 }
 
+// TypeImageSourceLoadFileId uses a file uploaded with the Files API.
+func TypeImageSourceLoadFileId(fileId string) RequestBodyMessagesMessagesContentTypeImageSource {
+	return RequestBodyMessagesMessagesContentTypeImageSource{
+		Type:   RequestBodyMessagesMessagesContentTypeImageSourceTypeFile,
+		FileId: fileId,
+	}
+}
+
 func TypeImageSourceLoadFile(filePath string) (RequestBodyMessagesMessagesContentTypeImageSource, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -0,0 +1,154 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const redacted = "[REDACTED]"
+
+// headers that are never logged
+var sensitiveHeaders = []string{"X-Api-Key", "Authorization"}
+
+// logRequest logs a finished request with its status, latency, request-id and number of attempts.
+func (c *Client) logRequest(ctx context.Context, req *http.Request, resp *http.Response, metadata ResponseMetadata, usage *ResponseBodyMessagesUsage, err error) {
+	if c.config.Logger == nil {
+		return
+	}
+
+	info, _ := RequestInfoFromContext(ctx)
+	attempts := 1
+	if info.attempts != nil && *info.attempts > 0 {
+		attempts = *info.attempts
+	}
+	attrs := []slog.Attr{
+		slog.String("operation", info.Operation),
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.Path),
+	}
+	if info.Model != "" {
+		attrs = append(attrs, slog.String("model", info.Model))
+	}
+	if info.Streaming {
+		attrs = append(attrs, slog.Bool("streaming", true))
+	}
+	attrs = append(attrs, slog.Int("attempts", attempts))
+
+	level := slog.LevelInfo
+	msg := "request completed"
+	if resp != nil {
+		attrs = append(attrs,
+			slog.Int("status", resp.StatusCode),
+			slog.Duration("latency", metadata.Latency),
+			slog.String("request_id", metadata.RequestID),
+		)
+	}
+	if usage != nil {
+		attrs = append(attrs, slog.Group("usage",
+			slog.Int64("input_tokens", usage.InputTokens),
+			slog.Int64("output_tokens", usage.OutputTokens),
+			slog.Int64("cache_creation_input_tokens", usage.CacheCreationInputTokens),
+			slog.Int64("cache_read_input_tokens", usage.CacheReadInputTokens),
+		))
+	}
+	if err != nil {
+		level = slog.LevelError
+		msg = "request failed"
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	c.config.Logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// logRetry logs a failed attempt that is retried after the delay.
+func (c *Client) logRetry(req *http.Request, attempt int, resp *http.Response, err error, delay time.Duration) {
+	if c.config.Logger == nil {
+		return
+	}
+
+	info, _ := RequestInfoFromContext(req.Context())
+	attrs := []slog.Attr{
+		slog.String("operation", info.Operation),
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.Path),
+		slog.Int("attempt", attempt+1),
+		slog.Duration("delay", delay),
+	}
+	if resp != nil {
+		attrs = append(attrs,
+			slog.Int("status", resp.StatusCode),
+			slog.String("request_id", resp.Header.Get("Request-Id")),
+		)
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	c.config.Logger.LogAttrs(req.Context(), slog.LevelWarn, "retrying request", attrs...)
+}
+
+// logBodies logs the headers and bodies of each attempt at debug level when LogBodies is set.
+// The X-Api-Key header is redacted and base64 data is shortened.
+// Only JSON response bodies are logged, so streams are not consumed.
+func (c *Client) logBodies(req *http.Request, next MiddlewareNext) (*http.Response, error) {
+	if c.config.Logger == nil || !c.config.LogBodies {
+		return next(req)
+	}
+
+	ctx := req.Context()
+	reqAttrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", req.URL.String()),
+		slog.Any("header", redactHeader(req.Header)),
+	}
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			b, _ := io.ReadAll(body)
+			body.Close()
+			reqAttrs = append(reqAttrs, slog.String("body", shortenBody(b)))
+		}
+	}
+	c.config.Logger.LogAttrs(ctx, slog.LevelDebug, "request body", reqAttrs...)
+
+	resp, err := next(req)
+	if err != nil {
+		return resp, err
+	}
+
+	respAttrs := []slog.Attr{
+		slog.Int("status", resp.StatusCode),
+		slog.Any("header", redactHeader(resp.Header)),
+	}
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), contentType) {
+		b, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(b))
+		if err != nil {
+			return nil, err
+		}
+		respAttrs = append(respAttrs, slog.String("body", shortenBody(b)))
+	}
+	c.config.Logger.LogAttrs(ctx, slog.LevelDebug, "response body", respAttrs...)
+	return resp, nil
+}
+
+func redactHeader(h http.Header) http.Header {
+	h = h.Clone()
+	for _, k := range sensitiveHeaders {
+		if h.Get(k) != "" {
+			h.Set(k, redacted)
+		}
+	}
+	return h
+}
+
+// shortenBody replaces long base64 data of images and documents with its size.
+func shortenBody(b []byte) string {
+	return string(base64DataPattern.ReplaceAllFunc(b, func(data []byte) []byte {
+		return []byte(fmt.Sprintf(`"data":"[%d bytes of base64]"`, len(data)-len(`"data":""`)))
+	}))
+}
@@ -0,0 +1,47 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestLogRequestMiddlewareResponse(t *testing.T) {
+	// the middleware answers itself, so its responses have no Request
+	statuses := []int{http.StatusServiceUnavailable, http.StatusOK}
+	fake := func(req *http.Request, next MiddlewareNext) (*http.Response, error) {
+		status := statuses[0]
+		statuses = statuses[1:]
+		return &http.Response{
+			StatusCode: status,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"id":"msg_1","usage":{"input_tokens":3,"output_tokens":1}}`)),
+		}, nil
+	}
+	var logs bytes.Buffer
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the request reached the server")
+	}, WithMiddleware(fake), WithLogger(slog.New(slog.NewJSONHandler(&logs, nil)), false))
+
+	if _, err := c.CreateMessages(context.Background(), RequestBodyMessages{Model: "m"}); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	var record struct {
+		Msg      string `json:"msg"`
+		Status   int    `json:"status"`
+		Attempts int    `json:"attempts"`
+	}
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &record); err != nil {
+		t.Fatalf("log %q: %v", logs.String(), err)
+	}
+	if record.Msg != "request completed" || record.Status != http.StatusOK || record.Attempts != 2 {
+		t.Errorf("log = %+v, want a completed request with status 200 after 2 attempts", record)
+	}
+}
@@ -1,61 +1,50 @@
 package v1
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
-	"fmt"
-	"net/http"
 )
 
-func (c *Client) CreateMessages(ctx context.Context, body RequestBodyMessages) (*ResponseBodyMessages, error) {
-	reqURL := c.config.BaseURL + c.config.Endpoint
-	reqHeaders := map[string]string{
-		"X-Api-Key":         c.config.ApiKey,
-		"Anthropic-Version": c.config.Version,
-		"Content-Type":      contentType,
-	}
-	if c.config.Beta != "" {
-		reqHeaders["Anthropic-Beta"] = c.config.Beta
-	}
+func (c *Client) CreateMessages(ctx context.Context, body RequestBodyMessages, opts ...RequestOption) (*ResponseBodyMessages, error) {
+	o := newRequestOptions(opts)
+	ctx, cancel := o.context(ctx)
+	defer cancel()
 
 	jsonBody, err := parseBodyJSON(body)
 	if err != nil {
 		return nil, err
 	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", reqURL, bytes.NewBuffer(jsonBody))
+	jsonBody, err = o.body(jsonBody)
 	if err != nil {
 		return nil, err
 	}
-	for k, v := range reqHeaders {
-		req.Header.Set(k, v)
-	}
 
-	resp, err := c.config.HTTPClient.Do(req)
+	ctx = withRequestInfo(ctx, RequestInfo{Operation: OperationCreateMessages, Model: body.Model, body: &body})
+	req, err := c.newRequest(ctx, "POST", c.config.Endpoint, jsonBody)
 	if err != nil {
 		return nil, err
 	}
+	addBetas(req, requiredBetas(body)...)
+	o.apply(req)
 
-	defer resp.Body.Close()
-
-	if resp.StatusCode == http.StatusOK {
-		var result ResponseBodyMessages
-		err = json.NewDecoder(resp.Body).Decode(&result)
-		if err != nil {
+	limiter := c.config.RateLimiter
+	var estimatedInputTokens int64
+	if limiter != nil {
+		estimatedInputTokens = estimateInputTokens(jsonBody)
+		if err := limiter.wait(ctx, estimatedInputTokens); err != nil {
 			return nil, err
 		}
-		return &result, nil
 	}
-	if (resp.StatusCode >= 400 && resp.StatusCode <= 500) || resp.StatusCode == 529 {
-		var result ResponseError
-		err = json.NewDecoder(resp.Body).Decode(&result)
-		if err != nil {
-			return nil, fmt.Errorf("json decode error: %w, status code: %d", err, resp.StatusCode)
-		}
-		return nil, fmt.Errorf("%s: %s", resp.Status, result.Error.Message)
+
+	var result ResponseBodyMessages
+	result.Metadata, err = c.do(req, &result)
+	if limiter != nil {
+		limiter.done(estimatedInputTokens, result.Usage, result.Metadata.RateLimit)
+	}
+	if err != nil {
+		return nil, err
 	}
-	return nil, fmt.Errorf("unexpected error: %d", resp.StatusCode)
+	return &result, nil
 }
 
 func parseBodyJSON(req RequestBodyMessages) ([]byte, error) {
@@ -66,21 +55,7 @@ func parseBodyJSON(req RequestBodyMessages) ([]byte, error) {
 			continue
 		}
 
-		var contentMulti []interface{}
-		if len(m.ContentTypeText) > 0 {
-			for j := range m.ContentTypeText {
-				m.ContentTypeText[j].Type = "text"
-				contentMulti = append(contentMulti, m.ContentTypeText[j])
-			}
-		}
-
-		if len(m.ContentTypeImage) > 0 {
-			for j := range m.ContentTypeImage {
-				m.ContentTypeImage[j].Type = "image"
-				contentMulti = append(contentMulti, m.ContentTypeImage[j])
-			}
-		}
-		raw, err := json.Marshal(contentMulti)
+		raw, err := json.Marshal(m.Blocks())
 		if err != nil {
 			return nil, err
 		}
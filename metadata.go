@@ -0,0 +1,58 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ResponseMetadata holds the response headers of a request.
+type ResponseMetadata struct {
+	RequestID      string
+	ProcessingTime time.Duration // x-envoy-upstream-service-time header, the processing time on the server
+	Latency        time.Duration // time until the response headers were received, including retries
+	RateLimit      ResponseRateLimit
+	Header         http.Header
+}
+
+// ResponseRateLimit holds the anthropic-ratelimit-* headers.
+// A limit the server did not send is zero.
+type ResponseRateLimit struct {
+	Requests     ResponseRateLimitValue // anthropic-ratelimit-requests-*
+	Tokens       ResponseRateLimitValue // anthropic-ratelimit-tokens-*, the most restrictive token limit
+	InputTokens  ResponseRateLimitValue // anthropic-ratelimit-input-tokens-*
+	OutputTokens ResponseRateLimitValue // anthropic-ratelimit-output-tokens-*
+}
+
+type ResponseRateLimitValue struct {
+	Limit     int64
+	Remaining int64
+	Reset     time.Time // when the limit is fully replenished
+}
+
+func newResponseMetadata(h http.Header, latency time.Duration) ResponseMetadata {
+	m := ResponseMetadata{
+		RequestID: h.Get("Request-Id"),
+		Latency:   latency,
+		RateLimit: ResponseRateLimit{
+			Requests:     parseRateLimitValue(h, "requests"),
+			Tokens:       parseRateLimitValue(h, "tokens"),
+			InputTokens:  parseRateLimitValue(h, "input-tokens"),
+			OutputTokens: parseRateLimitValue(h, "output-tokens"),
+		},
+		Header: h,
+	}
+	if ms, err := strconv.ParseInt(h.Get("X-Envoy-Upstream-Service-Time"), 10, 64); err == nil {
+		m.ProcessingTime = time.Duration(ms) * time.Millisecond
+	}
+	return m
+}
+
+func parseRateLimitValue(h http.Header, name string) ResponseRateLimitValue {
+	prefix := "Anthropic-Ratelimit-" + name + "-"
+	var v ResponseRateLimitValue
+	v.Limit, _ = strconv.ParseInt(h.Get(prefix+"Limit"), 10, 64)
+	v.Remaining, _ = strconv.ParseInt(h.Get(prefix+"Remaining"), 10, 64)
+	v.Reset, _ = time.Parse(time.RFC3339, h.Get(prefix+"Reset"))
+	return v
+}
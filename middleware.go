@@ -0,0 +1,77 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+)
+
+// MiddlewareNext sends the request to the next middleware, or to the HTTPClient after the last one.
+type MiddlewareNext func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps every HTTP request of the client, for all endpoints including streams.
+// It runs for each attempt, so retried requests pass through it again.
+// RequestInfoFromContext(req.Context()) returns the SDK context of the request.
+//
+//	func(req *http.Request, next claude.MiddlewareNext) (*http.Response, error) {
+//		info, _ := claude.RequestInfoFromContext(req.Context())
+//		start := time.Now()
+//		resp, err := next(req)
+//		log.Println(info.Operation, info.Model, info.Streaming, time.Since(start))
+//		return resp, err
+//	}
+type Middleware func(req *http.Request, next MiddlewareNext) (*http.Response, error)
+
+// Operations are the Client methods that sent the request.
+const (
+	OperationCreateMessages         = "CreateMessages"
+	OperationCreateMessagesStream   = "CreateMessagesStream"
+	OperationCountTokens            = "CountTokens"
+	OperationListModels             = "ListModels"
+	OperationGetModel               = "GetModel"
+	OperationCreateMessageBatch     = "CreateMessageBatch"
+	OperationGetMessageBatch        = "GetMessageBatch"
+	OperationListMessageBatches     = "ListMessageBatches"
+	OperationCancelMessageBatch     = "CancelMessageBatch"
+	OperationDeleteMessageBatch     = "DeleteMessageBatch"
+	OperationGetMessageBatchResults = "GetMessageBatchResults"
+)
+
+// RequestInfo is the SDK context of a request.
+type RequestInfo struct {
+	Operation string
+	Model     string // messages requests only
+	Streaming bool
+	Attempt   int // 0 for the first attempt, incremented for each retry
+
+	body     *RequestBodyMessages // messages requests only, for tracing
+	attempts *int                 // set by send, shared by the retries of a request
+}
+
+type requestInfoKey struct{}
+
+// RequestInfoFromContext returns the RequestInfo of a request sent by the client.
+func RequestInfoFromContext(ctx context.Context) (RequestInfo, bool) {
+	info, ok := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info, ok
+}
+
+func withRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	if info.attempts == nil {
+		info.attempts = new(int)
+	}
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// roundTrip sends the request through the middlewares and the HTTPClient.
+func (c *Client) roundTrip(req *http.Request) (*http.Response, error) {
+	next := func(req *http.Request) (*http.Response, error) {
+		return c.logBodies(req, c.doHTTP)
+	}
+	for i := len(c.config.Middlewares) - 1; i >= 0; i-- {
+		m, n := c.config.Middlewares[i], next
+		next = func(req *http.Request) (*http.Response, error) {
+			return m(req, n)
+		}
+	}
+	return next(req)
+}
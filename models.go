@@ -0,0 +1,91 @@
+package v1
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"time"
+)
+
+const modelsEndpoint = "v1/models"
+
+type ResponseBodyModel struct {
+	Type        string    `json:"type"` // always "model"
+	Id          string    `json:"id"`
+	DisplayName string    `json:"display_name"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type ResponseBodyModelsList struct {
+	Data    []ResponseBodyModel `json:"data"`
+	HasMore bool                `json:"has_more"`
+	FirstId string              `json:"first_id"`
+	LastId  string              `json:"last_id"`
+}
+
+// ListModels lists one page of the available models. More recently released models are listed first.
+func (c *Client) ListModels(ctx context.Context, params RequestListParams) (*ResponseBodyModelsList, error) {
+	ctx = withRequestInfo(ctx, RequestInfo{Operation: OperationListModels})
+	req, err := c.newRequest(ctx, "GET", modelsEndpoint+params.encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result ResponseBodyModelsList
+	_, err = c.do(req, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetModel retrieves a model by its id or alias.
+func (c *Client) GetModel(ctx context.Context, modelId string) (*ResponseBodyModel, error) {
+	ctx = withRequestInfo(ctx, RequestInfo{Operation: OperationGetModel})
+	req, err := c.newRequest(ctx, "GET", modelsEndpoint+"/"+url.PathEscape(modelId), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result ResponseBodyModel
+	_, err = c.do(req, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+type ModelsIterator struct {
+	client *Client
+	params RequestListParams
+	page   []ResponseBodyModel
+	done   bool
+}
+
+// ListModelsIterator returns an iterator over all models, starting at the page of params.
+// Pages are fetched on demand.
+func (c *Client) ListModelsIterator(params RequestListParams) *ModelsIterator {
+	return &ModelsIterator{
+		client: c,
+		params: params,
+	}
+}
+
+// Next returns the next model, or io.EOF when there are no more models.
+func (it *ModelsIterator) Next(ctx context.Context) (ResponseBodyModel, error) {
+	for len(it.page) == 0 {
+		if it.done {
+			return ResponseBodyModel{}, io.EOF
+		}
+		res, err := it.client.ListModels(ctx, it.params)
+		if err != nil {
+			return ResponseBodyModel{}, err
+		}
+		it.page = res.Data
+		it.params = it.params.next(res.FirstId, res.LastId)
+		it.done = !res.HasMore
+	}
+	m := it.page[0]
+	it.page = it.page[1:]
+	return m, nil
+}
@@ -0,0 +1,123 @@
+package v1
+
+import (
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	envAPIKey  = "ANTHROPIC_API_KEY"
+	envBaseURL = "ANTHROPIC_BASE_URL"
+)
+
+// Option configures the client created by NewClient.
+type Option func(*ClientConfig)
+
+func WithAPIKey(apiKey string) Option {
+	return func(c *ClientConfig) {
+		c.ApiKey = apiKey
+	}
+}
+
+func WithBaseURL(baseURL string) Option {
+	return func(c *ClientConfig) {
+		c.BaseURL = baseURL
+	}
+}
+
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *ClientConfig) {
+		c.HTTPClient = httpClient
+	}
+}
+
+// WithTimeout limits the time until the response headers of each attempt are received.
+// Reading the response body is not limited, so long streams are not cut off.
+// Use WithRequestTimeout to limit a whole call.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *ClientConfig) {
+		c.Timeout = timeout
+	}
+}
+
+// WithHeader adds a header that is sent with every request.
+func WithHeader(key, value string) Option {
+	return func(c *ClientConfig) {
+		if c.Header == nil {
+			c.Header = http.Header{}
+		}
+		c.Header.Add(key, value)
+	}
+}
+
+// WithBeta enables beta features with the anthropic-beta header.
+func WithBeta(beta ...string) Option {
+	return func(c *ClientConfig) {
+		c.Betas = append(c.Betas, beta...)
+	}
+}
+
+// WithMiddleware adds middlewares that wrap every request. The first one is the outermost.
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(c *ClientConfig) {
+		c.Middlewares = append(c.Middlewares, middlewares...)
+	}
+}
+
+// WithLogger logs each request of the client. Bodies are logged at debug level when logBodies is set.
+func WithLogger(logger *slog.Logger, logBodies bool) Option {
+	return func(c *ClientConfig) {
+		c.Logger = logger
+		c.LogBodies = logBodies
+	}
+}
+
+// WithTracerProvider creates an OpenTelemetry span for each call.
+//
+//	claude.WithTracerProvider(otel.GetTracerProvider())
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *ClientConfig) {
+		c.TracerProvider = tp
+	}
+}
+
+func WithVersion(version string) Option {
+	return func(c *ClientConfig) {
+		c.Version = version
+	}
+}
+
+// configFromEnv returns the default config, with the API key and base URL
+// taken from the ANTHROPIC_API_KEY and ANTHROPIC_BASE_URL environment variables.
+func configFromEnv() ClientConfig {
+	config := defaultConfig(os.Getenv(envAPIKey))
+	if baseURL := os.Getenv(envBaseURL); baseURL != "" {
+		config.BaseURL = baseURL
+	}
+	return config
+}
+
+// withDefaults fills the empty fields that the client cannot work without.
+func (c ClientConfig) withDefaults() ClientConfig {
+	if c.Version == "" {
+		c.Version = defaultVersion
+	}
+	if c.BaseURL == "" {
+		c.BaseURL = defaultBaseURL
+	}
+	if !strings.HasSuffix(c.BaseURL, "/") {
+		c.BaseURL += "/"
+	}
+	if c.Endpoint == "" {
+		c.Endpoint = defaultEndpoint
+	}
+	if c.HTTPClient == nil {
+		c.HTTPClient = &http.Client{}
+	}
+	return c
+}
@@ -0,0 +1,41 @@
+package v1
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// RequestListParams are the cursor pagination parameters of the list endpoints.
+type RequestListParams struct {
+	BeforeId string // optional
+	AfterId  string // optional
+	Limit    int    // optional, default 20
+}
+
+func (p RequestListParams) encode() string {
+	q := url.Values{}
+	if p.BeforeId != "" {
+		q.Set("before_id", p.BeforeId)
+	}
+	if p.AfterId != "" {
+		q.Set("after_id", p.AfterId)
+	}
+	if p.Limit > 0 {
+		q.Set("limit", strconv.Itoa(p.Limit))
+	}
+	if len(q) == 0 {
+		return ""
+	}
+	return "?" + q.Encode()
+}
+
+// next returns the parameters of the page after a page with the given first and last ids.
+// Paging goes backward when BeforeId is set.
+func (p RequestListParams) next(firstId string, lastId string) RequestListParams {
+	if p.BeforeId != "" {
+		p.BeforeId = firstId
+		return p
+	}
+	p.AfterId = lastId
+	return p
+}
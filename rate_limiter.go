@@ -0,0 +1,161 @@
+package v1
+
+import (
+	"context"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// tokens counted for each base64 image or document, as their size says little about their token count
+const estimatedBase64SourceTokens = 1600
+
+var base64DataPattern = regexp.MustCompile(`"data":"[A-Za-z0-9+/=]{1000,}"`)
+
+// RateLimiter throttles CreateMessages and CreateMessagesStream calls on the client side, so that
+// requests per minute (RPM), input tokens per minute (ITPM) and output tokens per minute (OTPM)
+// stay within the limits of the API key. It is safe to share between goroutines.
+//
+// Input tokens are estimated before each call and corrected from the usage of the response.
+// Output tokens are counted when the response arrives; a call waits while the output tokens are used up.
+// The anthropic-ratelimit-* response headers update the limiter with the state of the server,
+// but never raise a limit above the configured one.
+type RateLimiter struct {
+	mu           sync.Mutex
+	requests     tokenBucket
+	inputTokens  tokenBucket
+	outputTokens tokenBucket
+}
+
+// NewRateLimiter creates a limiter with per minute limits. A limit of 0 is not enforced.
+func NewRateLimiter(requestsPerMinute int64, inputTokensPerMinute int64, outputTokensPerMinute int64) *RateLimiter {
+	now := time.Now()
+	return &RateLimiter{
+		requests:     newTokenBucket(requestsPerMinute, now),
+		inputTokens:  newTokenBucket(inputTokensPerMinute, now),
+		outputTokens: newTokenBucket(outputTokensPerMinute, now),
+	}
+}
+
+// wait blocks until a request with the estimated input tokens fits within the limits, and takes it.
+func (l *RateLimiter) wait(ctx context.Context, inputTokens int64) error {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		l.requests.refill(now)
+		l.inputTokens.refill(now)
+		l.outputTokens.refill(now)
+
+		delay := l.requests.delay(1)
+		if d := l.inputTokens.delay(inputTokens); d > delay {
+			delay = d
+		}
+		if d := l.outputTokens.delay(1); d > delay {
+			delay = d
+		}
+		if delay == 0 {
+			l.requests.take(1)
+			l.inputTokens.take(inputTokens)
+			l.mu.Unlock()
+			return nil
+		}
+		l.mu.Unlock()
+
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// done corrects the estimated input tokens with the usage, and applies the rate-limit headers.
+func (l *RateLimiter) done(estimatedInputTokens int64, usage ResponseBodyMessagesUsage, rateLimit ResponseRateLimit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	l.requests.refill(now)
+	l.inputTokens.refill(now)
+	l.outputTokens.refill(now)
+
+	// cache reads do not count towards the input tokens limit
+	l.inputTokens.take(usage.InputTokens + usage.CacheCreationInputTokens - estimatedInputTokens)
+	l.outputTokens.take(usage.OutputTokens)
+
+	l.requests.update(rateLimit.Requests)
+	l.inputTokens.update(rateLimit.InputTokens)
+	l.outputTokens.update(rateLimit.OutputTokens)
+}
+
+func estimateInputTokens(jsonBody []byte) int64 {
+	sources := base64DataPattern.FindAllIndex(jsonBody, -1)
+	size := len(jsonBody)
+	for _, s := range sources {
+		size -= s[1] - s[0]
+	}
+	// about 4 characters per token
+	return int64(size/4) + int64(len(sources))*estimatedBase64SourceTokens
+}
+
+type tokenBucket struct {
+	limit     float64 // the configured limit, capacity never exceeds it
+	capacity  float64 // 0 means unlimited
+	available float64 // may be negative after a correction
+	last      time.Time
+}
+
+func newTokenBucket(perMinute int64, now time.Time) tokenBucket {
+	return tokenBucket{
+		limit:     float64(perMinute),
+		capacity:  float64(perMinute),
+		available: float64(perMinute),
+		last:      now,
+	}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if b.capacity == 0 {
+		return
+	}
+	b.available += now.Sub(b.last).Minutes() * b.capacity
+	if b.available > b.capacity {
+		b.available = b.capacity
+	}
+	b.last = now
+}
+
+// delay returns how long to wait until n tokens are available.
+// n is capped to the capacity, so a large request waits for a full bucket instead of forever.
+func (b *tokenBucket) delay(n int64) time.Duration {
+	if b.capacity == 0 {
+		return 0
+	}
+	need := float64(n)
+	if need > b.capacity {
+		need = b.capacity
+	}
+	if b.available >= need {
+		return 0
+	}
+	return time.Duration((need - b.available) / b.capacity * float64(time.Minute))
+}
+
+func (b *tokenBucket) take(n int64) {
+	if b.capacity == 0 {
+		return
+	}
+	b.available -= float64(n)
+}
+
+// update applies the limit and remaining values the server sent.
+// The server limit only lowers the capacity, as the configured limit may be a share of it.
+func (b *tokenBucket) update(v ResponseRateLimitValue) {
+	if b.capacity == 0 || v.Limit == 0 {
+		return
+	}
+	b.capacity = min(b.limit, float64(v.Limit))
+	if b.available > b.capacity {
+		b.available = b.capacity
+	}
+	if float64(v.Remaining) < b.available {
+		b.available = float64(v.Remaining)
+	}
+}
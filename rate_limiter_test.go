@@ -0,0 +1,152 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterWaitBlocksUntilCanceled(t *testing.T) {
+	l := NewRateLimiter(1, 0, 0)
+	if err := l.wait(context.Background(), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := l.wait(ctx, 0)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond || elapsed > time.Second {
+		t.Errorf("waited %s, want until the context is done", elapsed)
+	}
+}
+
+func TestRateLimiterCorrectsEstimate(t *testing.T) {
+	l := NewRateLimiter(0, 1000, 1000)
+	if err := l.wait(context.Background(), 400); err != nil {
+		t.Fatal(err)
+	}
+	l.done(400, ResponseBodyMessagesUsage{
+		InputTokens:              100,
+		CacheCreationInputTokens: 50,
+		CacheReadInputTokens:     500, // not counted
+		OutputTokens:             300,
+	}, ResponseRateLimit{})
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// the refill in between adds a fraction of a token
+	if got := l.inputTokens.available; got < 850 || got > 851 {
+		t.Errorf("input tokens available = %v, want 850", got)
+	}
+	if got := l.outputTokens.available; got < 700 || got > 701 {
+		t.Errorf("output tokens available = %v, want 700", got)
+	}
+}
+
+func TestRateLimiterUpdateFromHeaders(t *testing.T) {
+	l := NewRateLimiter(100, 1000, 0)
+	l.done(0, ResponseBodyMessagesUsage{}, ResponseRateLimit{
+		Requests:     ResponseRateLimitValue{Limit: 50, Remaining: 10},
+		InputTokens:  ResponseRateLimitValue{Limit: 1000, Remaining: 2000},
+		OutputTokens: ResponseRateLimitValue{Limit: 500, Remaining: 100},
+	})
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.requests.capacity != 50 || l.requests.available != 10 {
+		t.Errorf("requests = %v/%v, want 10/50", l.requests.available, l.requests.capacity)
+	}
+	// remaining above the local state does not raise it
+	if l.inputTokens.available > 1000 {
+		t.Errorf("input tokens available = %v, want at most 1000", l.inputTokens.available)
+	}
+	// a limit that is not enforced stays unlimited
+	if l.outputTokens.capacity != 0 {
+		t.Errorf("output tokens capacity = %v, want 0", l.outputTokens.capacity)
+	}
+}
+
+func TestRateLimiterUpdateKeepsConfiguredLimit(t *testing.T) {
+	l := NewRateLimiter(10, 0, 0)
+	tests := []struct {
+		name          string
+		header        ResponseRateLimitValue
+		wantCapacity  float64
+		wantAvailable float64
+	}{
+		{"above the configured limit", ResponseRateLimitValue{Limit: 4000, Remaining: 3999}, 10, 10},
+		{"below the configured limit", ResponseRateLimitValue{Limit: 5, Remaining: 4}, 5, 4},
+		{"back above the configured limit", ResponseRateLimitValue{Limit: 4000, Remaining: 3999}, 10, 4},
+	}
+	for _, tt := range tests {
+		l.done(0, ResponseBodyMessagesUsage{}, ResponseRateLimit{Requests: tt.header})
+
+		l.mu.Lock()
+		// the refill in between adds a fraction of a request
+		if got := l.requests; got.capacity != tt.wantCapacity || got.available < tt.wantAvailable || got.available > tt.wantAvailable+0.01 {
+			t.Errorf("%s: requests = %v/%v, want %v/%v", tt.name, got.available, got.capacity, tt.wantAvailable, tt.wantCapacity)
+		}
+		l.mu.Unlock()
+	}
+}
+
+func TestRateLimiterConcurrent(t *testing.T) {
+	l := NewRateLimiter(6000, 600000, 600000)
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := l.wait(context.Background(), 100); err != nil {
+				t.Error(err)
+				return
+			}
+			l.done(100, ResponseBodyMessagesUsage{InputTokens: 120, OutputTokens: 10}, ResponseRateLimit{
+				Requests: ResponseRateLimitValue{Limit: 6000, Remaining: 5000},
+			})
+		}()
+	}
+	wg.Wait()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.requests.available > 5000 {
+		t.Errorf("requests available = %v, want at most 5000", l.requests.available)
+	}
+}
+
+func TestRateLimiterStreamRequestTimeout(t *testing.T) {
+	c := NewClientWithConfig(ClientConfig{ApiKey: "key", RateLimiter: NewRateLimiter(1, 0, 0)})
+	c.config.RateLimiter.wait(context.Background(), 0)
+
+	start := time.Now()
+	_, err := c.CreateMessagesStream(context.Background(), RequestBodyMessages{Model: "m"}, WithRequestTimeout(50*time.Millisecond))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+	// the request timeout also bounds the wait for the limiter
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("waited %s, want the request timeout", elapsed)
+	}
+}
+
+func TestRateLimiterStreamRequestError(t *testing.T) {
+	c := NewClientWithConfig(ClientConfig{ApiKey: "key", BaseURL: "http://invalid\x7f/", RateLimiter: NewRateLimiter(0, 1000, 0)})
+
+	if _, err := c.CreateMessagesStream(context.Background(), RequestBodyMessages{Model: "m"}); err == nil {
+		t.Fatal("want an invalid URL error")
+	}
+	l := c.config.RateLimiter
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// the estimated input tokens are given back
+	if l.inputTokens.available < 999 {
+		t.Errorf("input tokens available = %v, want 1000", l.inputTokens.available)
+	}
+}
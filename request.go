@@ -1,32 +1,41 @@
 package v1
 
+import "encoding/json"
+
 type RequestBodyMessages struct {
-	Model          string                        `json:"model"`
-	Messages       []RequestBodyMessagesMessages `json:"messages"`
-	System         string                        `json:"-"`
-	SystemTypeText []RequestBodySystemTypeText   `json:"-"`
-	SystemRaw      interface{}                   `json:"system,omitempty"` // optional
-	MaxTokens      int                           `json:"max_tokens"`
-	Thinking       *RequestBodyMessagesThinking  `json:"thinking,omitempty"`    // optional
-	MetaData       map[string]interface{}        `json:"metadata"`              // optional
-	StopSequences  []string                      `json:"stop_sequences"`        // optional
-	Stream         bool                          `json:"stream"`                // optional
-	Temperature    float64                       `json:"temperature,omitempty"` // optional
-	TopP           float64                       `json:"top_p,omitempty"`       // optional
-	TopK           float64                       `json:"top_k,omitempty"`       // optional
+	Model          string                         `json:"model"`
+	Messages       []RequestBodyMessagesMessages  `json:"messages"`
+	System         string                         `json:"-"`
+	SystemTypeText []RequestBodySystemTypeText    `json:"-"`
+	SystemRaw      interface{}                    `json:"system,omitempty"` // optional
+	MaxTokens      int                            `json:"max_tokens"`
+	Thinking       *RequestBodyMessagesThinking   `json:"thinking,omitempty"`    // optional
+	MetaData       map[string]interface{}         `json:"metadata"`              // optional
+	StopSequences  []string                       `json:"stop_sequences"`        // optional
+	Stream         bool                           `json:"stream"`                // optional
+	Temperature    float64                        `json:"temperature,omitempty"` // optional
+	TopP           float64                        `json:"top_p,omitempty"`       // optional
+	TopK           float64                        `json:"top_k,omitempty"`       // optional
+	Tools          []RequestBodyMessagesTool      `json:"tools,omitempty"`       // optional
+	ToolChoice     *RequestBodyMessagesToolChoice `json:"tool_choice,omitempty"` // optional
 }
 
 type RequestBodyMessagesThinking struct {
 	Type         string `json:"type"`
 	BudgetTokens int    `json:"budget_tokens"`
+	Interleaved  bool   `json:"-"` // optional, think between tool calls. adds the interleaved-thinking beta
 }
 
 type RequestBodyMessagesMessages struct {
-	Role             string                                        `json:"role"`
-	ContentRaw       interface{}                                   `json:"content"`
-	Content          string                                        `json:"-"`
-	ContentTypeText  []RequestBodyMessagesMessagesContentTypeText  `json:"-"`
-	ContentTypeImage []RequestBodyMessagesMessagesContentTypeImage `json:"-"`
+	Role                  string                                             `json:"role"`
+	ContentRaw            interface{}                                        `json:"content"`
+	Content               string                                             `json:"-"`
+	ContentTypeText       []RequestBodyMessagesMessagesContentTypeText       `json:"-"`
+	ContentTypeImage      []RequestBodyMessagesMessagesContentTypeImage      `json:"-"`
+	ContentTypeDocument   []RequestBodyMessagesMessagesContentTypeDocument   `json:"-"`
+	ContentTypeToolUse    []RequestBodyMessagesMessagesContentTypeToolUse    `json:"-"` // assistant role only
+	ContentTypeToolResult []RequestBodyMessagesMessagesContentTypeToolResult `json:"-"` // user role only
+	ContentBlocks         []ContentBlock                                     `json:"-"` // sent in order, before the ContentType* blocks
 }
 
 type RequestBodySystemTypeText struct {
@@ -36,18 +45,30 @@ type RequestBodySystemTypeText struct {
 }
 
 type RequestCacheControl struct {
-	Type string `json:"type"` // always "ephemeral"
+	Type string `json:"type"`          // always "ephemeral"
+	Ttl  string `json:"ttl,omitempty"` // optional, "5m" or "1h". "1h" adds the extended-cache-ttl beta
 }
 
 const (
-	RequestBodyMessagesMessagesContentTypeTextType  = "text"
-	RequestBodyMessagesMessagesContentTypeImageType = "image"
+	CacheControlTtl5m = "5m"
+	CacheControlTtl1h = "1h"
+)
+
+const (
+	RequestBodyMessagesMessagesContentTypeTextType             = "text"
+	RequestBodyMessagesMessagesContentTypeImageType            = "image"
+	RequestBodyMessagesMessagesContentTypeDocumentType         = "document"
+	RequestBodyMessagesMessagesContentTypeToolUseType          = "tool_use"
+	RequestBodyMessagesMessagesContentTypeToolResultType       = "tool_result"
+	RequestBodyMessagesMessagesContentTypeThinkingType         = "thinking"
+	RequestBodyMessagesMessagesContentTypeRedactedThinkingType = "redacted_thinking"
 )
 
 type RequestBodyMessagesMessagesContentTypeText struct {
-	Type         string               `json:"type"` // always "text"
-	Text         string               `json:"text"`
-	CacheControl *RequestCacheControl `json:"cache_control"` // optional
+	Type         string                         `json:"type"` // always "text"
+	Text         string                         `json:"text"`
+	Citations    []ResponseBodyMessagesCitation `json:"citations,omitempty"` // optional, assistant role only
+	CacheControl *RequestCacheControl           `json:"cache_control"`       // optional
 }
 
 type RequestBodyMessagesMessagesContentTypeImage struct {
@@ -59,13 +80,76 @@ type RequestBodyMessagesMessagesContentTypeImage struct {
 const (
 	RequestBodyMessagesMessagesContentTypeImageSourceTypeBase64 = "base64"
 	RequestBodyMessagesMessagesContentTypeImageSourceTypeUrl    = "url"
+	RequestBodyMessagesMessagesContentTypeImageSourceTypeFile   = "file"
 )
 
 type RequestBodyMessagesMessagesContentTypeImageSource struct {
-	Type      string `json:"type"`                 // "base64" or "url"
+	Type      string `json:"type"`                 // "base64", "url" or "file"
 	MediaType string `json:"media_type,omitempty"` // base64 type required
 	Data      string `json:"data,omitempty"`       // base64 type required
 	Url       string `json:"url,omitempty"`        // url type required
+	FileId    string `json:"file_id,omitempty"`    // file type required, adds the files-api beta
+}
+
+type RequestBodyMessagesMessagesContentTypeToolUse struct {
+	Type         string               `json:"type"` // always "tool_use"
+	Id           string               `json:"id"`
+	Name         string               `json:"name"`
+	Input        json.RawMessage      `json:"input"`
+	CacheControl *RequestCacheControl `json:"cache_control"` // optional
+}
+
+type RequestBodyMessagesMessagesContentTypeToolResult struct {
+	Type             string                                        `json:"type"` // always "tool_result"
+	ToolUseId        string                                        `json:"tool_use_id"`
+	IsError          bool                                          `json:"is_error,omitempty"` // optional
+	ContentRaw       interface{}                                   `json:"content,omitempty"`
+	Content          string                                        `json:"-"`
+	ContentTypeText  []RequestBodyMessagesMessagesContentTypeText  `json:"-"`
+	ContentTypeImage []RequestBodyMessagesMessagesContentTypeImage `json:"-"`
+	ContentBlocks    []ContentBlock                                `json:"-"`             // text and image blocks only
+	CacheControl     *RequestCacheControl                          `json:"cache_control"` // optional
+}
+
+type RequestBodyMessagesMessagesContentTypeThinking struct {
+	Type      string `json:"type"` // always "thinking"
+	Thinking  string `json:"thinking"`
+	Signature string `json:"signature"`
+}
+
+type RequestBodyMessagesMessagesContentTypeRedactedThinking struct {
+	Type string `json:"type"` // always "redacted_thinking"
+	Data string `json:"data"`
+}
+
+type RequestBodyMessagesMessagesContentTypeDocument struct {
+	Type         string                                               `json:"type"` // always "document"
+	Source       RequestBodyMessagesMessagesContentTypeDocumentSource `json:"source"`
+	Title        string                                               `json:"title,omitempty"`     // optional
+	Context      string                                               `json:"context,omitempty"`   // optional
+	Citations    *RequestCitations                                    `json:"citations,omitempty"` // optional
+	CacheControl *RequestCacheControl                                 `json:"cache_control"`       // optional
+}
+
+type RequestCitations struct {
+	Enabled bool `json:"enabled"`
+}
+
+const (
+	RequestBodyMessagesMessagesContentTypeDocumentSourceTypeBase64  = "base64"
+	RequestBodyMessagesMessagesContentTypeDocumentSourceTypeText    = "text"
+	RequestBodyMessagesMessagesContentTypeDocumentSourceTypeUrl     = "url"
+	RequestBodyMessagesMessagesContentTypeDocumentSourceTypeContent = "content"
+	RequestBodyMessagesMessagesContentTypeDocumentSourceTypeFile    = "file"
+)
+
+type RequestBodyMessagesMessagesContentTypeDocumentSource struct {
+	Type      string         `json:"type"`                 // "base64", "text", "url", "content" or "file"
+	MediaType string         `json:"media_type,omitempty"` // base64 and text type required
+	Data      string         `json:"data,omitempty"`       // base64 and text type required
+	Url       string         `json:"url,omitempty"`        // url type required
+	Content   []ContentBlock `json:"content,omitempty"`    // content type required, text and image blocks only
+	FileId    string         `json:"file_id,omitempty"`    // file type required, adds the files-api beta
 }
 
 const (
@@ -0,0 +1,103 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// RequestOption configures a single CreateMessages or CreateMessagesStream call.
+type RequestOption func(*requestOptions)
+
+type requestOptions struct {
+	header      http.Header
+	betas       []string
+	timeout     time.Duration
+	extraFields map[string]interface{}
+}
+
+// WithRequestHeader adds a header to the request.
+func WithRequestHeader(key, value string) RequestOption {
+	return func(o *requestOptions) {
+		if o.header == nil {
+			o.header = http.Header{}
+		}
+		o.header.Add(key, value)
+	}
+}
+
+// WithRequestBeta enables beta features for the request, in addition to the betas of the client.
+func WithRequestBeta(beta ...string) RequestOption {
+	return func(o *requestOptions) {
+		o.betas = append(o.betas, beta...)
+	}
+}
+
+// WithRequestTimeout sets the timeout of the request.
+// For streams it covers the whole stream, until the last event is received.
+func WithRequestTimeout(timeout time.Duration) RequestOption {
+	return func(o *requestOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithExtraField sets a field of the request body, overwriting the field of RequestBodyMessages if any.
+// It allows sending API parameters that RequestBodyMessages does not have yet.
+//
+//	claude.WithExtraField("service_tier", "standard_only")
+func WithExtraField(key string, value interface{}) RequestOption {
+	return func(o *requestOptions) {
+		if o.extraFields == nil {
+			o.extraFields = map[string]interface{}{}
+		}
+		o.extraFields[key] = value
+	}
+}
+
+func newRequestOptions(opts []RequestOption) requestOptions {
+	var o requestOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// context returns the context with the timeout applied.
+func (o requestOptions) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if o.timeout > 0 {
+		return context.WithTimeout(ctx, o.timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// body adds the extra fields to the JSON body.
+func (o requestOptions) body(jsonBody []byte) ([]byte, error) {
+	if len(o.extraFields) == 0 {
+		return jsonBody, nil
+	}
+
+	var fields map[string]json.RawMessage
+	err := json.Unmarshal(jsonBody, &fields)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range o.extraFields {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		fields[k] = raw
+	}
+	return json.Marshal(fields)
+}
+
+// apply adds the headers and betas to the request.
+func (o requestOptions) apply(req *http.Request) {
+	for k, vs := range o.header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	addBetas(req, o.betas...)
+}
@@ -1,33 +1,129 @@
 package v1
 
+import "encoding/json"
+
 type ResponseBodyMessages struct {
 	Id           string                        `json:"id"`
 	Type         string                        `json:"type"` // always "message"
 	Role         string                        `json:"role"` // always "assistant"
 	Content      []ResponseBodyMessagesContent `json:"content"`
 	Model        string                        `json:"model"`
-	StopReason   string                        `json:"stop_reason"` // "end_turn" or "max_tokens", "stop_sequence", null
+	StopReason   string                        `json:"stop_reason"` // "end_turn" or "max_tokens", "stop_sequence", "tool_use", null
 	StopSequence string                        `json:"stop_sequence"`
-	Usage        struct {
-		InputTokens  int64 `json:"input_tokens"`
-		OutputTokens int64 `json:"output_tokens"`
-	} `json:"usage"`
+	Usage        ResponseBodyMessagesUsage     `json:"usage"`
+	Metadata     ResponseMetadata              `json:"-"`
+}
+
+type ResponseBodyMessagesUsage struct {
+	InputTokens              int64 `json:"input_tokens"`
+	OutputTokens             int64 `json:"output_tokens"`
+	CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+}
+
+// Add returns the sum of both usages.
+func (u ResponseBodyMessagesUsage) Add(v ResponseBodyMessagesUsage) ResponseBodyMessagesUsage {
+	return ResponseBodyMessagesUsage{
+		InputTokens:              u.InputTokens + v.InputTokens,
+		OutputTokens:             u.OutputTokens + v.OutputTokens,
+		CacheCreationInputTokens: u.CacheCreationInputTokens + v.CacheCreationInputTokens,
+		CacheReadInputTokens:     u.CacheReadInputTokens + v.CacheReadInputTokens,
+	}
 }
 
 const (
-	ResponseBodyMessagesContentTypeMessage  = "message"
-	ResponseBodyMessagesContentTypeText     = "text"
-	ResponseBodyMessagesContentTypeThinking = "thinking"
+	ResponseBodyMessagesContentTypeMessage          = "message"
+	ResponseBodyMessagesContentTypeText             = "text"
+	ResponseBodyMessagesContentTypeThinking         = "thinking"
+	ResponseBodyMessagesContentTypeToolUse          = "tool_use"
+	ResponseBodyMessagesContentTypeRedactedThinking = "redacted_thinking"
+)
+
+const (
+	ResponseBodyMessagesStopReasonEndTurn      = "end_turn"
+	ResponseBodyMessagesStopReasonMaxTokens    = "max_tokens"
+	ResponseBodyMessagesStopReasonStopSequence = "stop_sequence"
+	ResponseBodyMessagesStopReasonToolUse      = "tool_use"
 )
 
 type ResponseBodyMessagesContent struct {
-	Type     string `json:"type"`
-	Text     string `json:"text"`
-	Thinking string `json:"thinking"`
+	Type      string                         `json:"type"`
+	Text      string                         `json:"text"`
+	Citations []ResponseBodyMessagesCitation `json:"citations"` // text type only
+	Thinking  string                         `json:"thinking"`
+	Signature string                         `json:"signature"` // thinking type only
+	Data      string                         `json:"data"`      // redacted_thinking type only
+	Id        string                         `json:"id"`        // tool_use type only
+	Name      string                         `json:"name"`      // tool_use type only
+	Input     json.RawMessage                `json:"input"`     // tool_use type only
+}
+
+const (
+	ResponseBodyMessagesCitationTypeCharLocation         = "char_location"
+	ResponseBodyMessagesCitationTypePageLocation         = "page_location"
+	ResponseBodyMessagesCitationTypeContentBlockLocation = "content_block_location"
+	ResponseBodyMessagesCitationTypeSearchResultLocation = "search_result_location"
+)
+
+type ResponseBodyMessagesCitation struct {
+	Type              string `json:"type"`
+	CitedText         string `json:"cited_text"`
+	DocumentIndex     int64  `json:"document_index"`      // document locations only
+	DocumentTitle     string `json:"document_title"`      // document locations only
+	StartCharIndex    int64  `json:"start_char_index"`    // char_location type only
+	EndCharIndex      int64  `json:"end_char_index"`      // char_location type only
+	StartPageNumber   int64  `json:"start_page_number"`   // page_location type only
+	EndPageNumber     int64  `json:"end_page_number"`     // page_location type only
+	StartBlockIndex   int64  `json:"start_block_index"`   // content_block_location and search_result_location type only
+	EndBlockIndex     int64  `json:"end_block_index"`     // content_block_location and search_result_location type only
+	SearchResultIndex int64  `json:"search_result_index"` // search_result_location type only
+	Source            string `json:"source"`              // search_result_location type only
+	Title             string `json:"title"`               // search_result_location type only
+}
+
+// MarshalJSON writes only the fields of the citation type, so citations can be sent back in assistant messages.
+func (c ResponseBodyMessagesCitation) MarshalJSON() ([]byte, error) {
+	m := map[string]interface{}{
+		"type":       c.Type,
+		"cited_text": c.CitedText,
+	}
+	switch c.Type {
+	case ResponseBodyMessagesCitationTypeCharLocation:
+		c.putDocument(m)
+		m["start_char_index"] = c.StartCharIndex
+		m["end_char_index"] = c.EndCharIndex
+	case ResponseBodyMessagesCitationTypePageLocation:
+		c.putDocument(m)
+		m["start_page_number"] = c.StartPageNumber
+		m["end_page_number"] = c.EndPageNumber
+	case ResponseBodyMessagesCitationTypeContentBlockLocation:
+		c.putDocument(m)
+		m["start_block_index"] = c.StartBlockIndex
+		m["end_block_index"] = c.EndBlockIndex
+	case ResponseBodyMessagesCitationTypeSearchResultLocation:
+		m["search_result_index"] = c.SearchResultIndex
+		m["source"] = c.Source
+		m["title"] = c.Title
+		m["start_block_index"] = c.StartBlockIndex
+		m["end_block_index"] = c.EndBlockIndex
+	default:
+		type alias ResponseBodyMessagesCitation
+		return json.Marshal(alias(c))
+	}
+	return json.Marshal(m)
+}
+
+func (c ResponseBodyMessagesCitation) putDocument(m map[string]interface{}) {
+	m["document_index"] = c.DocumentIndex
+	if c.DocumentTitle != "" {
+		m["document_title"] = c.DocumentTitle
+	}
 }
 
 type ResponseError struct {
+	Type  string `json:"type"` // always "error"
 	Error struct {
+		Type    string `json:"type"`
 		Message string `json:"message"`
 	} `json:"error"`
 }
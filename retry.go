@@ -0,0 +1,145 @@
+package v1
+
+import (
+	"context"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultMaxRetries     = 2
+	defaultRetryBaseDelay = 500 * time.Millisecond
+	defaultRetryMaxDelay  = 8 * time.Second
+	defaultRetryJitter    = 0.25
+
+	// retry-after values above this are ignored in favor of the backoff delay
+	maxRetryAfter = time.Minute
+)
+
+// send sends the request, retrying failures that may succeed when sent again.
+// The returned response is the last one received, and may be an error response.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	info, _ := RequestInfoFromContext(req.Context())
+	for attempt := 0; ; attempt++ {
+		if info.attempts != nil {
+			*info.attempts = attempt + 1
+		}
+		if attempt > 0 {
+			info.Attempt = attempt
+			req = req.Clone(withRequestInfo(req.Context(), info))
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				req.Body = body
+			}
+		}
+
+		resp, err := c.roundTrip(req)
+		if attempt >= c.config.MaxRetries || !shouldRetry(req.Context(), resp, err) {
+			return resp, err
+		}
+
+		delay := c.retryDelay(attempt, resp)
+		c.logRetry(req, attempt, resp, err, delay)
+		if resp != nil {
+			// drain the body so the connection can be reused
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if err := sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// shouldRetry follows the x-should-retry header when the server sends it.
+// Otherwise connection errors, 408, 409, 429 and 5xx responses are retried.
+func shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		return ctx.Err() == nil
+	}
+	switch resp.Header.Get("X-Should-Retry") {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	switch resp.StatusCode {
+	case http.StatusRequestTimeout, http.StatusConflict, http.StatusTooManyRequests:
+		return true
+	}
+	return resp.StatusCode >= 500
+}
+
+// retryDelay honors the retry-after-ms and retry-after headers, and otherwise backs off exponentially.
+func (c *Client) retryDelay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if d, ok := retryAfter(resp.Header); ok && d <= maxRetryAfter {
+			return d
+		}
+	}
+
+	base := c.config.RetryBaseDelay
+	if base <= 0 {
+		base = defaultRetryBaseDelay
+	}
+	max := c.config.RetryMaxDelay
+	if max <= 0 {
+		max = defaultRetryMaxDelay
+	}
+	delay := time.Duration(float64(base) * math.Pow(2, float64(attempt)))
+	if delay > max || delay <= 0 {
+		delay = max
+	}
+	jitter := c.config.RetryJitter
+	if jitter > 0 && jitter <= 1 {
+		delay -= time.Duration(rand.Float64() * jitter * float64(delay))
+	}
+	return delay
+}
+
+func retryAfter(h http.Header) (time.Duration, bool) {
+	if v := h.Get("Retry-After-Ms"); v != "" {
+		if ms, err := strconv.ParseFloat(v, 64); err == nil && ms >= 0 {
+			return time.Duration(ms * float64(time.Millisecond)), true
+		}
+	}
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if s, err := strconv.ParseFloat(v, 64); err == nil && s >= 0 {
+		return time.Duration(s * float64(time.Second)), true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
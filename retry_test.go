@@ -0,0 +1,151 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingHandler answers with the statuses in order, and 200 afterwards, recording each request body.
+type recordingHandler struct {
+	mu       sync.Mutex
+	statuses []int
+	header   http.Header
+	bodies   []string
+	success  func(w http.ResponseWriter)
+}
+
+func (h *recordingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	h.mu.Lock()
+	attempt := len(h.bodies)
+	h.bodies = append(h.bodies, string(body))
+	h.mu.Unlock()
+
+	if attempt < len(h.statuses) {
+		for k, v := range h.header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(h.statuses[attempt])
+		w.Write([]byte(`{"type":"error","error":{"type":"api_error","message":"failed"}}`))
+		return
+	}
+	if h.success != nil {
+		h.success(w)
+		return
+	}
+	w.Write([]byte(`{"id":"msg_1","content":[]}`))
+}
+
+func (h *recordingHandler) attempts() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.bodies)
+}
+
+func TestRetryAfter(t *testing.T) {
+	h := &recordingHandler{
+		statuses: []int{http.StatusTooManyRequests},
+		header:   http.Header{"Retry-After": {"1"}},
+	}
+	c := newTestClient(t, h.ServeHTTP)
+
+	start := time.Now()
+	_, err := c.CreateMessages(context.Background(), RequestBodyMessages{Model: "m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.attempts() != 2 {
+		t.Errorf("attempts = %d, want 2", h.attempts())
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %s, want the retry-after delay of 1s", elapsed)
+	}
+}
+
+func TestRetryNotRetried(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		header http.Header
+	}{
+		{name: "400", status: http.StatusBadRequest},
+		{name: "500 with x-should-retry false", status: http.StatusInternalServerError, header: http.Header{"X-Should-Retry": {"false"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &recordingHandler{
+				statuses: []int{tt.status},
+				header:   tt.header,
+			}
+			c := newTestClient(t, h.ServeHTTP)
+
+			_, err := c.CreateMessages(context.Background(), RequestBodyMessages{Model: "m"})
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status {
+				t.Errorf("err = %v, want an APIError with status %d", err, tt.status)
+			}
+			if h.attempts() != 1 {
+				t.Errorf("attempts = %d, want 1", h.attempts())
+			}
+		})
+	}
+}
+
+func TestRetryResendsBody(t *testing.T) {
+	h := &recordingHandler{
+		statuses: []int{http.StatusInternalServerError, StatusOverloaded},
+	}
+	c := newTestClient(t, h.ServeHTTP)
+
+	_, err := c.CreateMessages(context.Background(), RequestBodyMessages{Model: "m", MaxTokens: 10, System: "be brief"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h.bodies) != 3 {
+		t.Fatalf("attempts = %d, want 3", len(h.bodies))
+	}
+	for i, body := range h.bodies {
+		if body == "" || body != h.bodies[0] {
+			t.Errorf("body of attempt %d = %q, want %q", i+1, body, h.bodies[0])
+		}
+	}
+}
+
+func TestRetryStreamBeforeFirstEvent(t *testing.T) {
+	h := &recordingHandler{
+		statuses: []int{http.StatusServiceUnavailable, StatusOverloaded},
+		success: func(w http.ResponseWriter) {
+			writeSSE(w,
+				[2]string{"message_start", `{"type":"message_start","message":{"id":"msg_1"}}`},
+				[2]string{"message_stop", `{"type":"message_stop"}`},
+			)
+		},
+	}
+	c := newTestClient(t, h.ServeHTTP)
+
+	stream, err := c.CreateMessagesStream(context.Background(), RequestBodyMessages{Model: "m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+	res, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Id != "msg_1" {
+		t.Errorf("Id = %q, want msg_1", res.Id)
+	}
+	if h.attempts() != 3 {
+		t.Errorf("attempts = %d, want 3", h.attempts())
+	}
+	for i, body := range h.bodies {
+		if body != h.bodies[0] {
+			t.Errorf("body of attempt %d = %q, want %q", i+1, body, h.bodies[0])
+		}
+	}
+}
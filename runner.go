@@ -0,0 +1,182 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const defaultToolRunnerMaxIterations = 10
+
+var ErrToolRunnerMaxIterations = errors.New("tool runner: max iterations reached")
+
+// ToolFunc executes a tool with the raw JSON input of a tool_use block.
+// The returned string is sent back as the tool_result content.
+// A returned error is sent back as a tool_result with is_error set.
+type ToolFunc func(ctx context.Context, input json.RawMessage) (string, error)
+
+type ToolRunner struct {
+	client    *Client
+	tools     []RequestBodyMessagesTool
+	toolFuncs map[string]ToolFunc
+
+	MaxIterations  int           // CreateMessages calls per Run, default 10
+	MaxConcurrency int           // tool calls executed at once, default 1. 0 or less means no limit
+	ToolTimeout    time.Duration // timeout for each tool call, 0 means no timeout
+}
+
+type ToolRunnerResult struct {
+	Messages   []RequestBodyMessagesMessages // the full transcript, including the final assistant message
+	Response   *ResponseBodyMessages         // the last response
+	Usage      ResponseBodyMessagesUsage     // the sum of the usage of every response
+	Iterations int
+}
+
+func NewToolRunner(client *Client) *ToolRunner {
+	return &ToolRunner{
+		client:         client,
+		toolFuncs:      map[string]ToolFunc{},
+		MaxIterations:  defaultToolRunnerMaxIterations,
+		MaxConcurrency: 1,
+	}
+}
+
+// Register adds a tool and the function that executes it.
+// Registering a tool with the same name again replaces it.
+func (r *ToolRunner) Register(tool RequestBodyMessagesTool, fn ToolFunc) {
+	if _, ok := r.toolFuncs[tool.Name]; ok {
+		for i := range r.tools {
+			if r.tools[i].Name == tool.Name {
+				r.tools[i] = tool
+			}
+		}
+	} else {
+		r.tools = append(r.tools, tool)
+	}
+	r.toolFuncs[tool.Name] = fn
+}
+
+// Run calls CreateMessages and executes every returned tool_use until the stop reason
+// is no longer "tool_use". The registered tools are added to body.Tools.
+//
+// If MaxIterations is reached, the result so far is returned with ErrToolRunnerMaxIterations.
+func (r *ToolRunner) Run(ctx context.Context, body RequestBodyMessages) (*ToolRunnerResult, error) {
+	body.Messages = append([]RequestBodyMessagesMessages{}, body.Messages...)
+	body.Tools = r.mergeTools(body.Tools)
+
+	result := &ToolRunnerResult{}
+	for result.Iterations < r.MaxIterations {
+		res, err := r.client.CreateMessages(ctx, body)
+		if err != nil {
+			result.Messages = body.Messages
+			return result, err
+		}
+		result.Iterations++
+		result.Response = res
+		result.Usage = result.Usage.Add(res.Usage)
+		body.Messages = append(body.Messages, res.Message())
+
+		if res.StopReason != ResponseBodyMessagesStopReasonToolUse {
+			result.Messages = body.Messages
+			return result, nil
+		}
+
+		toolResults := RequestBodyMessagesMessages{
+			Role:                  MessagesRoleUser,
+			ContentTypeToolResult: r.execute(ctx, res.ToolUses()),
+		}
+		body.Messages = append(body.Messages, toolResults)
+	}
+	result.Messages = body.Messages
+	return result, ErrToolRunnerMaxIterations
+}
+
+func (r *ToolRunner) mergeTools(tools []RequestBodyMessagesTool) []RequestBodyMessagesTool {
+	merged := append([]RequestBodyMessagesTool{}, tools...)
+	for _, t := range r.tools {
+		exists := false
+		for _, v := range tools {
+			if v.Name == t.Name {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			merged = append(merged, t)
+		}
+	}
+	return merged
+}
+
+// execute runs the tool calls with up to MaxConcurrency workers.
+// The results are in the same order as toolUses.
+func (r *ToolRunner) execute(ctx context.Context, toolUses []ResponseBodyMessagesContent) []RequestBodyMessagesMessagesContentTypeToolResult {
+	results := make([]RequestBodyMessagesMessagesContentTypeToolResult, len(toolUses))
+	workers := r.MaxConcurrency
+	if workers <= 0 || workers > len(toolUses) {
+		workers = len(toolUses)
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = r.call(ctx, toolUses[i])
+			}
+		}()
+	}
+	for i := range toolUses {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return results
+}
+
+func (r *ToolRunner) call(ctx context.Context, toolUse ResponseBodyMessagesContent) RequestBodyMessagesMessagesContentTypeToolResult {
+	fn, ok := r.toolFuncs[toolUse.Name]
+	if !ok {
+		return UseToolResultError(toolUse.Id, fmt.Sprintf("tool %q not found", toolUse.Name))
+	}
+
+	if r.ToolTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.ToolTimeout)
+		defer cancel()
+	}
+
+	type output struct {
+		out string
+		err error
+	}
+	// buffered, so the goroutine can finish even if nobody waits for it anymore
+	done := make(chan output, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- output{err: fmt.Errorf("tool %q panicked: %v", toolUse.Name, p)}
+			}
+		}()
+		out, err := fn(ctx, toolUse.Input)
+		done <- output{out: out, err: err}
+	}()
+
+	select {
+	case o := <-done:
+		if o.err != nil {
+			return UseToolResultError(toolUse.Id, o.err.Error())
+		}
+		return UseToolResult(toolUse.Id, o.out)
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && r.ToolTimeout > 0 {
+			return UseToolResultError(toolUse.Id, fmt.Sprintf("tool %q timed out after %s", toolUse.Name, r.ToolTimeout))
+		}
+		return UseToolResultError(toolUse.Id, ctx.Err().Error())
+	}
+}
@@ -0,0 +1,151 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func toolUse(id string, name string) ResponseBodyMessagesContent {
+	return ResponseBodyMessagesContent{
+		Type:  ResponseBodyMessagesContentTypeToolUse,
+		Id:    id,
+		Name:  name,
+		Input: json.RawMessage(`{}`),
+	}
+}
+
+func TestToolRunnerExecuteConcurrentInOrder(t *testing.T) {
+	r := NewToolRunner(nil)
+	r.MaxConcurrency = 2
+
+	var mu sync.Mutex
+	running, peak := 0, 0
+	for i, delay := range []time.Duration{150, 100, 50, 10} {
+		name := fmt.Sprintf("tool_%d", i)
+		delay := delay * time.Millisecond
+		r.Register(RequestBodyMessagesTool{Name: name}, func(ctx context.Context, input json.RawMessage) (string, error) {
+			mu.Lock()
+			running++
+			if running > peak {
+				peak = running
+			}
+			mu.Unlock()
+			time.Sleep(delay)
+			mu.Lock()
+			running--
+			mu.Unlock()
+			return name + " done", nil
+		})
+	}
+
+	start := time.Now()
+	results := r.execute(context.Background(), []ResponseBodyMessagesContent{
+		toolUse("toolu_0", "tool_0"),
+		toolUse("toolu_1", "tool_1"),
+		toolUse("toolu_2", "tool_2"),
+		toolUse("toolu_3", "tool_3"),
+	})
+	elapsed := time.Since(start)
+
+	if peak != 2 {
+		t.Errorf("peak concurrency = %d, want 2", peak)
+	}
+	// sequentially the tools take 310ms
+	if elapsed >= 300*time.Millisecond {
+		t.Errorf("took %s, want the tools to run in parallel", elapsed)
+	}
+	for i, res := range results {
+		wantId := fmt.Sprintf("toolu_%d", i)
+		wantContent := fmt.Sprintf("tool_%d done", i)
+		if res.ToolUseId != wantId || res.Content != wantContent || res.IsError {
+			t.Errorf("results[%d] = %s %q is_error=%v, want %s %q", i, res.ToolUseId, res.Content, res.IsError, wantId, wantContent)
+		}
+	}
+}
+
+func TestToolRunnerExecuteErrors(t *testing.T) {
+	r := NewToolRunner(nil)
+	r.MaxConcurrency = 0
+	r.ToolTimeout = 50 * time.Millisecond
+	r.Register(RequestBodyMessagesTool{Name: "panics"}, func(ctx context.Context, input json.RawMessage) (string, error) {
+		panic("boom")
+	})
+	r.Register(RequestBodyMessagesTool{Name: "slow"}, func(ctx context.Context, input json.RawMessage) (string, error) {
+		time.Sleep(time.Second)
+		return "too late", nil
+	})
+	r.Register(RequestBodyMessagesTool{Name: "fails"}, func(ctx context.Context, input json.RawMessage) (string, error) {
+		return "", fmt.Errorf("no such city")
+	})
+
+	start := time.Now()
+	results := r.execute(context.Background(), []ResponseBodyMessagesContent{
+		toolUse("toolu_0", "panics"),
+		toolUse("toolu_1", "slow"),
+		toolUse("toolu_2", "fails"),
+		toolUse("toolu_3", "unknown"),
+	})
+	if elapsed := time.Since(start); elapsed >= time.Second {
+		t.Errorf("took %s, want the slow tool to time out", elapsed)
+	}
+
+	want := []string{
+		`tool "panics" panicked: boom`,
+		`tool "slow" timed out after 50ms`,
+		`no such city`,
+		`tool "unknown" not found`,
+	}
+	for i, res := range results {
+		if res.ToolUseId != fmt.Sprintf("toolu_%d", i) || !res.IsError || res.Content != want[i] {
+			t.Errorf("results[%d] = %s %q is_error=%v, want an error %q", i, res.ToolUseId, res.Content, res.IsError, want[i])
+		}
+	}
+}
+
+func TestToolRunnerRun(t *testing.T) {
+	var requests []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, string(body))
+		if len(requests) == 1 {
+			w.Write([]byte(`{"id":"msg_1","stop_reason":"tool_use","content":[
+				{"type":"tool_use","id":"toolu_a","name":"echo","input":{"text":"a"}},
+				{"type":"tool_use","id":"toolu_b","name":"echo","input":{"text":"b"}}
+			],"usage":{"input_tokens":10,"output_tokens":5}}`))
+			return
+		}
+		w.Write([]byte(`{"id":"msg_2","stop_reason":"end_turn","content":[{"type":"text","text":"done"}],"usage":{"input_tokens":20,"output_tokens":1}}`))
+	})
+
+	r := NewToolRunner(c)
+	r.MaxConcurrency = 2
+	r.Register(RequestBodyMessagesTool{Name: "echo"}, func(ctx context.Context, input json.RawMessage) (string, error) {
+		var v struct{ Text string }
+		if err := json.Unmarshal(input, &v); err != nil {
+			return "", err
+		}
+		return v.Text, nil
+	})
+
+	result, err := r.Run(context.Background(), RequestBodyMessages{
+		Model:    "m",
+		Messages: []RequestBodyMessagesMessages{{Role: MessagesRoleUser, Content: "echo a and b"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Iterations != 2 || len(result.Messages) != 4 || result.Usage.InputTokens != 30 {
+		t.Errorf("result = %d iterations, %d messages, %d input tokens, want 2, 4, 30", result.Iterations, len(result.Messages), result.Usage.InputTokens)
+	}
+	want := `"content":[{"type":"tool_result","tool_use_id":"toolu_a","content":"a","cache_control":null},{"type":"tool_result","tool_use_id":"toolu_b","content":"b","cache_control":null}]`
+	if len(requests) != 2 || !strings.Contains(requests[1], want) {
+		t.Errorf("second request = %s, want the tool results %s", requests[len(requests)-1], want)
+	}
+}
@@ -0,0 +1,267 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JSONSchema is the subset of JSON Schema used for tool input_schema.
+type JSONSchema struct {
+	Type                 string                 `json:"type,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	AdditionalProperties *JSONSchema            `json:"additionalProperties,omitempty"`
+	Enum                 []interface{}          `json:"enum,omitempty"`
+	Minimum              *float64               `json:"minimum,omitempty"`
+	Maximum              *float64               `json:"maximum,omitempty"`
+	MinLength            *int                   `json:"minLength,omitempty"`
+	MaxLength            *int                   `json:"maxLength,omitempty"`
+	MinItems             *int                   `json:"minItems,omitempty"`
+	MaxItems             *int                   `json:"maxItems,omitempty"`
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// NewTool builds a tool definition whose input_schema is generated from the struct type of input.
+//
+// Property names follow the json tags. The jsonschema tag adds constraints as a comma separated list:
+//
+//	type WeatherInput struct {
+//		Location string `json:"location" jsonschema:"required,description=The city and state"`
+//		Unit     string `json:"unit,omitempty" jsonschema:"enum=celsius,enum=fahrenheit"`
+//		Days     int    `json:"days,omitempty" jsonschema:"minimum=1,maximum=7"`
+//	}
+//
+// Supported keys are required, description, enum, minimum, maximum, minLength, maxLength,
+// minItems and maxItems. Write \\, in the tag to put a comma in a description.
+func NewTool(name string, description string, input interface{}) (RequestBodyMessagesTool, error) {
+	schema, err := GenerateJSONSchema(input)
+	if err != nil {
+		return RequestBodyMessagesTool{}, err
+	}
+	return RequestBodyMessagesTool{
+		Name:        name,
+		Description: description,
+		InputSchema: schema,
+	}, nil
+}
+
+// GenerateJSONSchema generates an object schema from the struct type of v.
+func GenerateJSONSchema(v interface{}) (*JSONSchema, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("input schema must be generated from a struct, got %v", t)
+	}
+	return schemaOf(t, map[reflect.Type]bool{})
+}
+
+// UnmarshalInput decodes the input of a tool_use block into v.
+func (c ResponseBodyMessagesContent) UnmarshalInput(v interface{}) error {
+	if c.Type != ResponseBodyMessagesContentTypeToolUse {
+		return fmt.Errorf("content type is %q, not %q", c.Type, ResponseBodyMessagesContentTypeToolUse)
+	}
+	return json.Unmarshal(c.Input, v)
+}
+
+func schemaOf(t reflect.Type, visiting map[reflect.Type]bool) (*JSONSchema, error) {
+	if t == timeType {
+		return &JSONSchema{Type: "string", Format: "date-time"}, nil
+	}
+	if t == rawMessageType {
+		return &JSONSchema{}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return schemaOf(t.Elem(), visiting)
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &JSONSchema{Type: "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}, nil
+	case reflect.String:
+		return &JSONSchema{Type: "string"}, nil
+	case reflect.Interface:
+		return &JSONSchema{}, nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// encoding/json encodes []byte as a base64 string
+			return &JSONSchema{Type: "string"}, nil
+		}
+		items, err := schemaOf(t.Elem(), visiting)
+		if err != nil {
+			return nil, err
+		}
+		return &JSONSchema{Type: "array", Items: items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("unsupported map key type %v", t.Key())
+		}
+		values, err := schemaOf(t.Elem(), visiting)
+		if err != nil {
+			return nil, err
+		}
+		return &JSONSchema{Type: "object", AdditionalProperties: values}, nil
+	case reflect.Struct:
+		if visiting[t] {
+			return nil, fmt.Errorf("recursive type %v is not supported", t)
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		s := &JSONSchema{Type: "object", Properties: map[string]*JSONSchema{}}
+		if err := addStructFields(s, t, visiting); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+	return nil, fmt.Errorf("unsupported type %v", t)
+}
+
+func addStructFields(s *JSONSchema, t reflect.Type, visiting map[reflect.Type]bool) error {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		jsonTag := f.Tag.Get("json")
+		if jsonTag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(jsonTag, ",")
+
+		ft := f.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		// embedded structs without a json name are flattened, as encoding/json does
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			if err := addStructFields(s, ft, visiting); err != nil {
+				return err
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		prop, err := schemaOf(f.Type, visiting)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", f.Name, err)
+		}
+		required, err := applySchemaTag(prop, f.Tag.Get("jsonschema"))
+		if err != nil {
+			return fmt.Errorf("field %s: %w", f.Name, err)
+		}
+		s.Properties[name] = prop
+		if required {
+			s.Required = append(s.Required, name)
+		}
+	}
+	return nil
+}
+
+func applySchemaTag(s *JSONSchema, tag string) (required bool, err error) {
+	for _, option := range splitSchemaTag(tag) {
+		key, value, _ := strings.Cut(option, "=")
+		switch key {
+		case "":
+		case "required":
+			required = true
+		case "description":
+			s.Description = value
+		case "enum":
+			e, err := parseSchemaValue(s.Type, value)
+			if err != nil {
+				return false, err
+			}
+			s.Enum = append(s.Enum, e)
+		case "minimum", "maximum":
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return false, fmt.Errorf("invalid %s %q", key, value)
+			}
+			if key == "minimum" {
+				s.Minimum = &f
+			} else {
+				s.Maximum = &f
+			}
+		case "minLength", "maxLength", "minItems", "maxItems":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return false, fmt.Errorf("invalid %s %q", key, value)
+			}
+			switch key {
+			case "minLength":
+				s.MinLength = &n
+			case "maxLength":
+				s.MaxLength = &n
+			case "minItems":
+				s.MinItems = &n
+			case "maxItems":
+				s.MaxItems = &n
+			}
+		default:
+			return false, fmt.Errorf("unknown jsonschema tag option %q", key)
+		}
+	}
+	return required, nil
+}
+
+func splitSchemaTag(tag string) []string {
+	var options []string
+	var b strings.Builder
+	for i := 0; i < len(tag); i++ {
+		if tag[i] == '\\' && i+1 < len(tag) && tag[i+1] == ',' {
+			b.WriteByte(',')
+			i++
+			continue
+		}
+		if tag[i] == ',' {
+			options = append(options, b.String())
+			b.Reset()
+			continue
+		}
+		b.WriteByte(tag[i])
+	}
+	return append(options, b.String())
+}
+
+func parseSchemaValue(schemaType string, value string) (interface{}, error) {
+	switch schemaType {
+	case "integer":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer enum %q", value)
+		}
+		return n, nil
+	case "number":
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number enum %q", value)
+		}
+		return f, nil
+	case "boolean":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid boolean enum %q", value)
+		}
+		return b, nil
+	}
+	return value, nil
+}
@@ -1,14 +1,17 @@
 package v1
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/tmaxmax/go-sse"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -22,12 +25,35 @@ const (
 	MessagesStreamResponseTypeError             = "error"
 )
 
+// ErrStreamClosed is logged and recorded on the span of a stream that is closed before its message is complete.
+var ErrStreamClosed = errors.New("stream: closed before the message was complete")
+
 type CreateMessagesStream struct {
 	Connection                 *sse.Connection
 	Unsubscribe                func()
 	Event                      chan sse.Event
 	Error                      chan error
 	ResponseBodyMessagesStream ResponseBodyMessagesStream
+	Metadata                   ResponseMetadata // set when the first event is received
+
+	toolUses map[int64]*toolUseStream // tool_use blocks in progress, by content block index
+
+	client               *Client
+	req                  *http.Request
+	resp                 *http.Response // set when the response headers are received
+	estimatedInputTokens int64
+	start                time.Time
+	span                 trace.Span
+	firstToken           bool
+	finishOnce           sync.Once
+	cancel               context.CancelFunc
+	done                 chan struct{} // closed when the connection goroutine exits
+}
+
+type toolUseStream struct {
+	id          string
+	name        string
+	partialJson strings.Builder
 }
 
 type ResponseBodyMessagesStream struct {
@@ -39,15 +65,23 @@ type ResponseBodyMessagesStream struct {
 	StopReason   string                              `json:"stop_reason"` // "end_turn" or "max_tokens", "stop_sequence", null
 	StopSequence string                              `json:"stop_sequence"`
 	Usage        struct {
-		InputTokens  int64 `json:"input_tokens"`
-		OutputTokens int64 `json:"output_tokens"`
+		InputTokens              int64 `json:"input_tokens"`
+		OutputTokens             int64 `json:"output_tokens"`
+		CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+		CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
 	} `json:"usage"`
 }
 
 type ResponseBodyMessagesContentStream struct {
-	Type     string `json:"type"`
-	Text     string `json:"text"`
-	Thinking string `json:"thinking"`
+	Type        string                         `json:"type"`
+	Text        string                         `json:"text"`
+	Thinking    string                         `json:"thinking"`
+	Citations   []ResponseBodyMessagesCitation `json:"citations"`    // text type only
+	Index       int64                          `json:"index"`        // tool_use type only
+	Id          string                         `json:"id"`           // tool_use type only
+	Name        string                         `json:"name"`         // tool_use type only
+	PartialJson string                         `json:"partial_json"` // tool_use type only, a fragment of the input
+	Input       json.RawMessage                `json:"input"`        // tool_use type only, set when the block is complete
 }
 
 type ResponseContentMessageStartStream struct {
@@ -55,16 +89,29 @@ type ResponseContentMessageStartStream struct {
 	Message ResponseBodyMessagesStream `json:"message"`
 }
 
+type ResponseContentBlockStartStream struct {
+	Type         string                            `json:"type"`
+	Index        int64                             `json:"index"`
+	ContentBlock ResponseBodyMessagesContentStream `json:"content_block"`
+}
+
 type ResponseContentBlockDeltaStream struct {
 	Type  string `json:"type"`
 	Index int64  `json:"index"`
 	Delta struct {
-		Type     string `json:"type"`
-		Text     string `json:"text"`
-		Thinking string `json:"thinking"`
+		Type        string                       `json:"type"`
+		Text        string                       `json:"text"`
+		Thinking    string                       `json:"thinking"`
+		PartialJson string                       `json:"partial_json"`
+		Citation    ResponseBodyMessagesCitation `json:"citation"`
 	} `json:"delta"`
 }
 
+type ResponseContentBlockStopStream struct {
+	Type  string `json:"type"`
+	Index int64  `json:"index"`
+}
+
 type ResponseMessageDeltaStream struct {
 	Type  string `json:"type"`
 	Delta struct {
@@ -76,151 +123,314 @@ type ResponseMessageDeltaStream struct {
 	} `json:"usage"`
 }
 
-func (c *Client) CreateMessagesStream(ctx context.Context, body RequestBodyMessages) (*CreateMessagesStream, error) {
-	reqURL := c.config.BaseURL + c.config.Endpoint
+func (c *Client) CreateMessagesStream(ctx context.Context, body RequestBodyMessages, opts ...RequestOption) (*CreateMessagesStream, error) {
+	o := newRequestOptions(opts)
 	body.Stream = true
-	reqHeaders := map[string]string{
-		"X-Api-Key":         c.config.ApiKey,
-		"Anthropic-Version": c.config.Version,
-		"Content-Type":      contentType,
-	}
-	if c.config.Beta != "" {
-		reqHeaders["anthropic-beta"] = c.config.Beta
-	}
 
 	jsonBody, err := parseBodyJSON(body)
 	if err != nil {
 		return nil, err
 	}
+	jsonBody, err = o.body(jsonBody)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := o.context(ctx)
+	var estimatedInputTokens int64
+	if c.config.RateLimiter != nil {
+		estimatedInputTokens = estimateInputTokens(jsonBody)
+		if err := c.config.RateLimiter.wait(ctx, estimatedInputTokens); err != nil {
+			cancel()
+			return nil, err
+		}
+	}
+
+	info := RequestInfo{Operation: OperationCreateMessagesStream, Model: body.Model, Streaming: true, body: &body}
+	ctx, span := c.startSpan(withRequestInfo(ctx, info), info)
 
+	start := time.Now()
+	stream := &CreateMessagesStream{
+		ResponseBodyMessagesStream: ResponseBodyMessagesStream{},
+		toolUses:                   map[int64]*toolUseStream{},
+		client:                     c,
+		estimatedInputTokens:       estimatedInputTokens,
+		start:                      start,
+		span:                       span,
+	}
 	client := sse.Client{
-		HTTPClient: c.config.HTTPClient,
+		// failures before the first event are retried by send.
+		// The stream itself is not reconnected, as that would generate the message again.
+		HTTPClient: &http.Client{
+			Transport: roundTripperFunc(c.send),
+		},
 		Backoff: sse.Backoff{
 			MaxRetries: -1,
 		},
+		ResponseValidator: func(resp *http.Response) error {
+			stream.resp = resp
+			stream.Metadata = newResponseMetadata(resp.Header, time.Since(start))
+			if resp.StatusCode != http.StatusOK {
+				err := newAPIError(resp)
+				stream.finish(err)
+				return err
+			}
+			return sse.DefaultValidator(resp)
+		},
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", reqURL, bytes.NewBuffer(jsonBody))
+	req, err := c.newRequest(ctx, "POST", c.config.Endpoint, jsonBody)
 	if err != nil {
+		if c.config.RateLimiter != nil {
+			c.config.RateLimiter.done(estimatedInputTokens, ResponseBodyMessagesUsage{}, ResponseRateLimit{})
+		}
+		endSpan(span, ResponseMetadata{}, 0, err)
+		cancel()
 		return nil, err
 	}
-	for k, v := range reqHeaders {
-		req.Header.Set(k, v)
-	}
+	addBetas(req, requiredBetas(body)...)
+	o.apply(req)
 
 	conn := client.NewConnection(req)
 	chanEvent := make(chan sse.Event)
-	connectionError := make(chan error)
+	// buffered, so the error is kept for Recv when the context is done
+	connectionError := make(chan error, 1)
 
 	unsubscribe := conn.SubscribeToAll(func(e sse.Event) {
-		if e.Type == MessagesStreamResponseTypePing ||
-			e.Type == MessagesStreamResponseTypeContentBlockStart ||
-			e.Type == MessagesStreamResponseTypeContentBlockStop {
+		if e.Type == MessagesStreamResponseTypePing {
 			return
 		}
-		chanEvent <- e
+		select {
+		case chanEvent <- e:
+		case <-ctx.Done():
+		}
 	})
+	done := make(chan struct{})
 	go func() {
+		defer close(done)
 		err := conn.Connect()
 		if !errors.Is(err, io.EOF) && err != nil {
+			if stream.resp == nil {
+				stream.finish(err)
+			}
 			connectionError <- err
 		}
 	}()
-	return &CreateMessagesStream{
-		Connection:                 conn,
-		Unsubscribe:                unsubscribe,
-		Event:                      chanEvent,
-		Error:                      connectionError,
-		ResponseBodyMessagesStream: ResponseBodyMessagesStream{},
-	}, nil
+	stream.Connection = conn
+	stream.Unsubscribe = unsubscribe
+	stream.Event = chanEvent
+	stream.Error = connectionError
+	stream.req = req
+	stream.cancel = cancel
+	stream.done = done
+	return stream, nil
+}
+
+// finish corrects the rate limiter with the usage of the stream, logs it and ends the span, once.
+func (c *CreateMessagesStream) finish(err error) {
+	c.finishOnce.Do(func() {
+		r := c.ResponseBodyMessagesStream
+		usage := ResponseBodyMessagesUsage{
+			InputTokens:              r.Usage.InputTokens,
+			OutputTokens:             r.Usage.OutputTokens,
+			CacheCreationInputTokens: r.Usage.CacheCreationInputTokens,
+			CacheReadInputTokens:     r.Usage.CacheReadInputTokens,
+		}
+		if c.client.config.RateLimiter != nil {
+			c.client.config.RateLimiter.done(c.estimatedInputTokens, usage, c.Metadata.RateLimit)
+		}
+
+		var statusCode int
+		if c.resp != nil {
+			statusCode = c.resp.StatusCode
+		}
+		if err != nil {
+			c.client.logRequest(c.req.Context(), c.req, c.resp, c.Metadata, nil, err)
+			endSpan(c.span, c.Metadata, statusCode, err)
+			return
+		}
+		c.client.logRequest(c.req.Context(), c.req, c.resp, c.Metadata, &usage, nil)
+		setSpanResponse(c.span, r.Id, r.Model, r.StopReason, usage)
+		endSpan(c.span, c.Metadata, statusCode, nil)
+	})
 }
 
+// Close stops the stream. The channels are closed after the connection has stopped,
+// so no event or error is sent on them afterwards.
 func (c *CreateMessagesStream) Close() {
+	c.cancel()
+	<-c.done
+	c.Unsubscribe()
 	close(c.Event)
 	close(c.Error)
-	c.Unsubscribe()
+	// a stream that is still incomplete is logged and traced as failed
+	c.finish(ErrStreamClosed)
 }
 
+// Recv returns the next event of the stream. Content[0] holds the delta of the event.
+//
+// A tool_use block is returned three ways: with Id and Name when the block starts,
+// with PartialJson for each input_json_delta, and with the assembled Input when the block stops.
 func (c *CreateMessagesStream) Recv() (ResponseBodyMessagesStream, error) {
-	select {
-	case e := <-c.Event:
-		switch e.Type {
-		case MessagesStreamResponseTypeMessageStart:
-			d := []byte(e.Data)
-			var r ResponseContentMessageStartStream
-			err := json.Unmarshal(d, &r)
-			if err != nil {
-				return ResponseBodyMessagesStream{}, err
-			}
-			c.ResponseBodyMessagesStream = r.Message
-			c.ResponseBodyMessagesStream.Content = []ResponseBodyMessagesContentStream{
-				{
-					Type:     "message",
-					Text:     "",
-					Thinking: "",
-				},
-			}
-			return c.ResponseBodyMessagesStream, nil
-		case MessagesStreamResponseTypeContentBlockDelta:
-			d := []byte(e.Data)
-			var r ResponseContentBlockDeltaStream
-			err := json.Unmarshal(d, &r)
-			if err != nil {
-				return ResponseBodyMessagesStream{}, err
-			}
-			if r.Delta.Type == "thinking_delta" {
+	for {
+		select {
+		case e := <-c.Event:
+			switch e.Type {
+			case MessagesStreamResponseTypeMessageStart:
+				d := []byte(e.Data)
+				var r ResponseContentMessageStartStream
+				err := json.Unmarshal(d, &r)
+				if err != nil {
+					return ResponseBodyMessagesStream{}, err
+				}
+				c.ResponseBodyMessagesStream = r.Message
 				c.ResponseBodyMessagesStream.Content = []ResponseBodyMessagesContentStream{
 					{
-						Type:     "thinking",
+						Type:     "message",
 						Text:     "",
-						Thinking: r.Delta.Thinking,
+						Thinking: "",
 					},
 				}
-			}
-
-			if r.Delta.Type == "text_delta" {
+				return c.ResponseBodyMessagesStream, nil
+			case MessagesStreamResponseTypeContentBlockStart:
+				d := []byte(e.Data)
+				var r ResponseContentBlockStartStream
+				err := json.Unmarshal(d, &r)
+				if err != nil {
+					return ResponseBodyMessagesStream{}, err
+				}
+				if r.ContentBlock.Type != ResponseBodyMessagesContentTypeToolUse {
+					continue
+				}
+				c.toolUses[r.Index] = &toolUseStream{
+					id:   r.ContentBlock.Id,
+					name: r.ContentBlock.Name,
+				}
 				c.ResponseBodyMessagesStream.Content = []ResponseBodyMessagesContentStream{
 					{
-						Type:     "text",
-						Text:     r.Delta.Text,
-						Thinking: "",
+						Type:  ResponseBodyMessagesContentTypeToolUse,
+						Index: r.Index,
+						Id:    r.ContentBlock.Id,
+						Name:  r.ContentBlock.Name,
 					},
 				}
-			}
+				return c.ResponseBodyMessagesStream, nil
+			case MessagesStreamResponseTypeContentBlockDelta:
+				d := []byte(e.Data)
+				var r ResponseContentBlockDeltaStream
+				err := json.Unmarshal(d, &r)
+				if err != nil {
+					return ResponseBodyMessagesStream{}, err
+				}
+				if !c.firstToken {
+					c.firstToken = true
+					setSpanFirstToken(c.span, c.start)
+				}
+				switch r.Delta.Type {
+				case "thinking_delta":
+					c.ResponseBodyMessagesStream.Content = []ResponseBodyMessagesContentStream{
+						{
+							Type:     "thinking",
+							Text:     "",
+							Thinking: r.Delta.Thinking,
+						},
+					}
+				case "text_delta":
+					c.ResponseBodyMessagesStream.Content = []ResponseBodyMessagesContentStream{
+						{
+							Type:     "text",
+							Text:     r.Delta.Text,
+							Thinking: "",
+						},
+					}
+				case "citations_delta":
+					c.ResponseBodyMessagesStream.Content = []ResponseBodyMessagesContentStream{
+						{
+							Type:      "text",
+							Citations: []ResponseBodyMessagesCitation{r.Delta.Citation},
+						},
+					}
+				case "input_json_delta":
+					t, ok := c.toolUses[r.Index]
+					if !ok {
+						continue
+					}
+					t.partialJson.WriteString(r.Delta.PartialJson)
+					c.ResponseBodyMessagesStream.Content = []ResponseBodyMessagesContentStream{
+						{
+							Type:        ResponseBodyMessagesContentTypeToolUse,
+							Index:       r.Index,
+							Id:          t.id,
+							Name:        t.name,
+							PartialJson: r.Delta.PartialJson,
+						},
+					}
+				default:
+					continue
+				}
+				return c.ResponseBodyMessagesStream, nil
+			case MessagesStreamResponseTypeContentBlockStop:
+				d := []byte(e.Data)
+				var r ResponseContentBlockStopStream
+				err := json.Unmarshal(d, &r)
+				if err != nil {
+					return ResponseBodyMessagesStream{}, err
+				}
+				t, ok := c.toolUses[r.Index]
+				if !ok {
+					continue
+				}
+				delete(c.toolUses, r.Index)
+				input := json.RawMessage(t.partialJson.String())
+				if len(input) == 0 {
+					input = json.RawMessage("{}")
+				}
+				c.ResponseBodyMessagesStream.Content = []ResponseBodyMessagesContentStream{
+					{
+						Type:  ResponseBodyMessagesContentTypeToolUse,
+						Index: r.Index,
+						Id:    t.id,
+						Name:  t.name,
+						Input: input,
+					},
+				}
+				return c.ResponseBodyMessagesStream, nil
+			case MessagesStreamResponseTypeMessageDelta:
+				d := []byte(e.Data)
+				var r ResponseMessageDeltaStream
+				err := json.Unmarshal(d, &r)
+				if err != nil {
+					return ResponseBodyMessagesStream{}, err
+				}
+				c.ResponseBodyMessagesStream.Content = []ResponseBodyMessagesContentStream{}
+				c.ResponseBodyMessagesStream.StopReason = r.Delta.StopReason
+				c.ResponseBodyMessagesStream.StopSequence = r.Delta.StopSequence
+				c.ResponseBodyMessagesStream.Usage.OutputTokens = r.Usage.OutputTokens
+				c.finish(nil)
+				c.ResponseBodyMessagesStream.Content = []ResponseBodyMessagesContentStream{
+					{
+						Type: "message",
+						Text: "",
+					},
+				}
+				return c.ResponseBodyMessagesStream, nil
 
-			return c.ResponseBodyMessagesStream, nil
-		case MessagesStreamResponseTypeMessageDelta:
-			d := []byte(e.Data)
-			var r ResponseMessageDeltaStream
-			err := json.Unmarshal(d, &r)
-			if err != nil {
-				return ResponseBodyMessagesStream{}, err
-			}
-			c.ResponseBodyMessagesStream.Content = []ResponseBodyMessagesContentStream{}
-			c.ResponseBodyMessagesStream.StopReason = r.Delta.StopReason
-			c.ResponseBodyMessagesStream.StopSequence = r.Delta.StopSequence
-			c.ResponseBodyMessagesStream.Usage.OutputTokens = r.Usage.OutputTokens
-			c.ResponseBodyMessagesStream.Content = []ResponseBodyMessagesContentStream{
-				{
-					Type: "message",
-					Text: "",
-				},
-			}
-			return c.ResponseBodyMessagesStream, nil
-
-		case MessagesStreamResponseTypeMessageStop:
-			return c.ResponseBodyMessagesStream, io.EOF
-		case MessagesStreamResponseTypeError:
-			d := []byte(e.Data)
-			var r ResponseError
-			err := json.Unmarshal(d, &r)
-			if err != nil {
-				return ResponseBodyMessagesStream{}, err
+			case MessagesStreamResponseTypeMessageStop:
+				return c.ResponseBodyMessagesStream, io.EOF
+			case MessagesStreamResponseTypeError:
+				d := []byte(e.Data)
+				apiErr, err := newAPIErrorFromEvent(d)
+				if err != nil {
+					return ResponseBodyMessagesStream{}, err
+				}
+				apiErr.RequestID = c.Metadata.RequestID
+				c.finish(apiErr)
+				return c.ResponseBodyMessagesStream, apiErr
 			}
-			return c.ResponseBodyMessagesStream, errors.New(r.Error.Message)
+		case err := <-c.Error:
+			c.finish(err)
+			return ResponseBodyMessagesStream{}, err
 		}
-	case err := <-c.Error:
-		return ResponseBodyMessagesStream{}, err
+		return c.ResponseBodyMessagesStream, nil
 	}
-	return c.ResponseBodyMessagesStream, nil
 }
@@ -0,0 +1,196 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCreateMessagesStreamCloseEarly(t *testing.T) {
+	release := make(chan struct{})
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("event: message_start\ndata: {\"message\":{\"id\":\"msg_1\"}}\n\n"))
+		w.(http.Flusher).Flush()
+		// keep the stream open until the test ends
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+	defer close(release)
+	var logs bytes.Buffer
+	c.config.Logger = slog.New(slog.NewJSONHandler(&logs, nil))
+
+	stream, err := c.CreateMessagesStream(context.Background(), RequestBodyMessages{Model: "m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Id != "msg_1" {
+		t.Errorf("Id = %q, want msg_1", res.Id)
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		stream.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close did not return")
+	}
+	// give a late send on the closed channels the chance to panic
+	time.Sleep(50 * time.Millisecond)
+
+	var record struct {
+		Level string `json:"level"`
+		Msg   string `json:"msg"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(logs.Bytes(), &record); err != nil {
+		t.Fatalf("log %q: %v", logs.String(), err)
+	}
+	if record.Level != "ERROR" || record.Msg != "request failed" || record.Error != ErrStreamClosed.Error() {
+		t.Errorf("log = %+v, want a failed request with ErrStreamClosed", record)
+	}
+}
+
+func TestCreateMessagesStreamToolUse(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeSSE(w,
+			[2]string{"message_start", `{"type":"message_start","message":{"id":"msg_1","role":"assistant"}}`},
+			[2]string{"content_block_start", `{"type":"content_block_start","index":0,"content_block":{"type":"tool_use","id":"toolu_1","name":"get_weather","input":{}}}`},
+			[2]string{"ping", `{"type":"ping"}`},
+			[2]string{"content_block_delta", `{"type":"content_block_delta","index":0,"delta":{"type":"input_json_delta","partial_json":""}}`},
+			[2]string{"content_block_delta", `{"type":"content_block_delta","index":0,"delta":{"type":"input_json_delta","partial_json":"{\"city\": \"To"}}`},
+			[2]string{"content_block_delta", `{"type":"content_block_delta","index":0,"delta":{"type":"input_json_delta","partial_json":"kyo\"}"}}`},
+			[2]string{"content_block_stop", `{"type":"content_block_stop","index":0}`},
+			[2]string{"content_block_start", `{"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"toolu_2","name":"get_time","input":{}}}`},
+			[2]string{"content_block_stop", `{"type":"content_block_stop","index":1}`},
+			[2]string{"message_delta", `{"type":"message_delta","delta":{"stop_reason":"tool_use"},"usage":{"output_tokens":20}}`},
+			[2]string{"message_stop", `{"type":"message_stop"}`},
+		)
+	})
+
+	stream, err := c.CreateMessagesStream(context.Background(), RequestBodyMessages{Model: "m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+
+	var blocks []ResponseBodyMessagesContentStream
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			if res.StopReason != "tool_use" {
+				t.Errorf("StopReason = %q, want tool_use", res.StopReason)
+			}
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Content[0].Type == ResponseBodyMessagesContentTypeToolUse {
+			blocks = append(blocks, res.Content[0])
+		}
+	}
+
+	want := []ResponseBodyMessagesContentStream{
+		{Index: 0, Id: "toolu_1", Name: "get_weather"},
+		{Index: 0, Id: "toolu_1", Name: "get_weather", PartialJson: ""},
+		{Index: 0, Id: "toolu_1", Name: "get_weather", PartialJson: `{"city": "To`},
+		{Index: 0, Id: "toolu_1", Name: "get_weather", PartialJson: `kyo"}`},
+		{Index: 0, Id: "toolu_1", Name: "get_weather", Input: json.RawMessage(`{"city": "Tokyo"}`)},
+		{Index: 1, Id: "toolu_2", Name: "get_time"},
+		// no input_json_delta, the input falls back to an empty object
+		{Index: 1, Id: "toolu_2", Name: "get_time", Input: json.RawMessage(`{}`)},
+	}
+	if len(blocks) != len(want) {
+		t.Fatalf("got %d tool_use events, want %d: %+v", len(blocks), len(want), blocks)
+	}
+	for i, w := range want {
+		b := blocks[i]
+		if b.Index != w.Index || b.Id != w.Id || b.Name != w.Name || b.PartialJson != w.PartialJson || string(b.Input) != string(w.Input) {
+			t.Errorf("event %d = %+v, want %+v", i, b, w)
+		}
+	}
+}
+
+func TestCreateMessagesStreamContextDone(t *testing.T) {
+	release := make(chan struct{})
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("event: message_start\ndata: {\"message\":{\"id\":\"msg_1\"}}\n\n"))
+		w.(http.Flusher).Flush()
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+	defer close(release)
+
+	tests := []struct {
+		name string
+		open func() (*CreateMessagesStream, context.CancelFunc, error)
+		want error
+	}{
+		{
+			name: "canceled",
+			open: func() (*CreateMessagesStream, context.CancelFunc, error) {
+				ctx, cancel := context.WithCancel(context.Background())
+				stream, err := c.CreateMessagesStream(ctx, RequestBodyMessages{Model: "m"})
+				return stream, cancel, err
+			},
+			want: context.Canceled,
+		},
+		{
+			name: "request timeout",
+			open: func() (*CreateMessagesStream, context.CancelFunc, error) {
+				stream, err := c.CreateMessagesStream(context.Background(), RequestBodyMessages{Model: "m"}, WithRequestTimeout(30*time.Millisecond))
+				return stream, func() {}, err
+			},
+			want: context.DeadlineExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the error used to be dropped now and then, so try a few times
+			for i := 0; i < 10; i++ {
+				stream, cancel, err := tt.open()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, err := stream.Recv(); err != nil {
+					t.Fatal(err)
+				}
+				cancel()
+
+				recvErr := make(chan error, 1)
+				go func() {
+					_, err := stream.Recv()
+					recvErr <- err
+				}()
+				select {
+				case err := <-recvErr:
+					if !errors.Is(err, tt.want) {
+						t.Errorf("err = %v, want %v", err, tt.want)
+					}
+				case <-time.After(5 * time.Second):
+					t.Fatal("Recv did not return after the context was done")
+				}
+				stream.Close()
+			}
+		})
+	}
+}
@@ -7,3 +7,10 @@ func UseThinking(budgetTokens int) *RequestBodyMessagesThinking {
 	}
 	return &t
 }
+
+// UseInterleavedThinking lets the model think between tool calls.
+func UseInterleavedThinking(budgetTokens int) *RequestBodyMessagesThinking {
+	t := UseThinking(budgetTokens)
+	t.Interleaved = true
+	return t
+}
@@ -0,0 +1,98 @@
+package v1
+
+const (
+	RequestBodyMessagesToolChoiceTypeAuto = "auto"
+	RequestBodyMessagesToolChoiceTypeAny  = "any"
+	RequestBodyMessagesToolChoiceTypeTool = "tool"
+	RequestBodyMessagesToolChoiceTypeNone = "none"
+)
+
+type RequestBodyMessagesTool struct {
+	Name         string               `json:"name"`
+	Description  string               `json:"description,omitempty"`   // optional
+	InputSchema  interface{}          `json:"input_schema"`            // JSON Schema object
+	CacheControl *RequestCacheControl `json:"cache_control,omitempty"` // optional
+}
+
+type RequestBodyMessagesToolChoice struct {
+	Type                   string `json:"type"`                                // "auto", "any", "tool" or "none"
+	Name                   string `json:"name,omitempty"`                      // tool type required
+	DisableParallelToolUse bool   `json:"disable_parallel_tool_use,omitempty"` // optional, not for none type
+}
+
+// UseToolChoiceAuto lets Claude decide whether to use a tool. This is the default when tools are set.
+func UseToolChoiceAuto() *RequestBodyMessagesToolChoice {
+	t := RequestBodyMessagesToolChoice{
+		Type: RequestBodyMessagesToolChoiceTypeAuto,
+	}
+	return &t
+}
+
+// UseToolChoiceAny forces Claude to use one of the tools.
+func UseToolChoiceAny() *RequestBodyMessagesToolChoice {
+	t := RequestBodyMessagesToolChoice{
+		Type: RequestBodyMessagesToolChoiceTypeAny,
+	}
+	return &t
+}
+
+// UseToolChoiceTool forces Claude to use the named tool.
+func UseToolChoiceTool(name string) *RequestBodyMessagesToolChoice {
+	t := RequestBodyMessagesToolChoice{
+		Type: RequestBodyMessagesToolChoiceTypeTool,
+		Name: name,
+	}
+	return &t
+}
+
+// UseToolChoiceNone prevents Claude from using any tool.
+func UseToolChoiceNone() *RequestBodyMessagesToolChoice {
+	t := RequestBodyMessagesToolChoice{
+		Type: RequestBodyMessagesToolChoiceTypeNone,
+	}
+	return &t
+}
+
+// DisableParallel makes Claude use at most one tool (auto type) or exactly one tool (any and tool types).
+func (t *RequestBodyMessagesToolChoice) DisableParallel() *RequestBodyMessagesToolChoice {
+	t.DisableParallelToolUse = true
+	return t
+}
+
+// ToolUses returns the tool_use blocks of the response in order.
+func (r *ResponseBodyMessages) ToolUses() []ResponseBodyMessagesContent {
+	var toolUses []ResponseBodyMessagesContent
+	for _, c := range r.Content {
+		if c.Type == ResponseBodyMessagesContentTypeToolUse {
+			toolUses = append(toolUses, c)
+		}
+	}
+	return toolUses
+}
+
+func UseToolResult(toolUseId string, content string) RequestBodyMessagesMessagesContentTypeToolResult {
+	return RequestBodyMessagesMessagesContentTypeToolResult{
+		Type:      RequestBodyMessagesMessagesContentTypeToolResultType,
+		ToolUseId: toolUseId,
+		Content:   content,
+	}
+}
+
+func UseToolResultError(toolUseId string, content string) RequestBodyMessagesMessagesContentTypeToolResult {
+	return RequestBodyMessagesMessagesContentTypeToolResult{
+		Type:      RequestBodyMessagesMessagesContentTypeToolResultType,
+		ToolUseId: toolUseId,
+		IsError:   true,
+		Content:   content,
+	}
+}
+
+// ToolUse converts a tool_use response block so it can be sent back in the assistant message.
+func (c ResponseBodyMessagesContent) ToolUse() RequestBodyMessagesMessagesContentTypeToolUse {
+	return RequestBodyMessagesMessagesContentTypeToolUse{
+		Type:  RequestBodyMessagesMessagesContentTypeToolUseType,
+		Id:    c.Id,
+		Name:  c.Name,
+		Input: c.Input,
+	}
+}
@@ -0,0 +1,120 @@
+package v1
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/potproject/claude-sdk-go"
+
+// OpenTelemetry GenAI semantic conventions
+const (
+	attrGenAISystem                = attribute.Key("gen_ai.system")
+	attrGenAIOperationName         = attribute.Key("gen_ai.operation.name")
+	attrGenAIRequestModel          = attribute.Key("gen_ai.request.model")
+	attrGenAIRequestMaxTokens      = attribute.Key("gen_ai.request.max_tokens")
+	attrGenAIRequestTemperature    = attribute.Key("gen_ai.request.temperature")
+	attrGenAIRequestTopP           = attribute.Key("gen_ai.request.top_p")
+	attrGenAIRequestTopK           = attribute.Key("gen_ai.request.top_k")
+	attrGenAIRequestStopSequences  = attribute.Key("gen_ai.request.stop_sequences")
+	attrGenAIResponseId            = attribute.Key("gen_ai.response.id")
+	attrGenAIResponseModel         = attribute.Key("gen_ai.response.model")
+	attrGenAIResponseFinishReasons = attribute.Key("gen_ai.response.finish_reasons")
+	attrGenAIUsageInputTokens      = attribute.Key("gen_ai.usage.input_tokens")
+	attrGenAIUsageOutputTokens     = attribute.Key("gen_ai.usage.output_tokens")
+
+	// seconds from the start of the call until the first content delta of a stream
+	attrGenAIResponseTimeToFirstToken = attribute.Key("gen_ai.response.time_to_first_token")
+
+	attrServerAddress      = attribute.Key("server.address")
+	attrServerPort         = attribute.Key("server.port")
+	attrHTTPResponseStatus = attribute.Key("http.response.status_code")
+	attrRequestId          = attribute.Key("anthropic.request_id")
+
+	genAISystemAnthropic = "anthropic"
+	genAIOperationChat   = "chat"
+)
+
+// startSpan starts the span of a call. Without a TracerProvider the returned span does nothing.
+func (c *Client) startSpan(ctx context.Context, info RequestInfo) (context.Context, trace.Span) {
+	if c.config.TracerProvider == nil {
+		return ctx, trace.SpanFromContext(context.Background())
+	}
+
+	name := info.Operation
+	attrs := []attribute.KeyValue{
+		attrGenAISystem.String(genAISystemAnthropic),
+	}
+	if u, err := url.Parse(c.config.BaseURL); err == nil {
+		attrs = append(attrs, attrServerAddress.String(u.Hostname()))
+		if port, err := strconv.Atoi(u.Port()); err == nil {
+			attrs = append(attrs, attrServerPort.Int(port))
+		}
+	}
+	if info.Model != "" {
+		attrs = append(attrs, attrGenAIRequestModel.String(info.Model))
+	}
+	if body := info.body; body != nil && info.Operation != OperationCountTokens {
+		name = genAIOperationChat + " " + body.Model
+		attrs = append(attrs,
+			attrGenAIOperationName.String(genAIOperationChat),
+			attrGenAIRequestMaxTokens.Int(body.MaxTokens),
+		)
+		if body.Temperature != 0 {
+			attrs = append(attrs, attrGenAIRequestTemperature.Float64(body.Temperature))
+		}
+		if body.TopP != 0 {
+			attrs = append(attrs, attrGenAIRequestTopP.Float64(body.TopP))
+		}
+		if body.TopK != 0 {
+			attrs = append(attrs, attrGenAIRequestTopK.Float64(body.TopK))
+		}
+		if len(body.StopSequences) > 0 {
+			attrs = append(attrs, attrGenAIRequestStopSequences.StringSlice(body.StopSequences))
+		}
+	}
+
+	tracer := c.config.TracerProvider.Tracer(tracerName)
+	return tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// setSpanResponse adds the response of a messages call to the span.
+func setSpanResponse(span trace.Span, id string, model string, stopReason string, usage ResponseBodyMessagesUsage) {
+	attrs := []attribute.KeyValue{
+		attrGenAIResponseId.String(id),
+		attrGenAIResponseModel.String(model),
+		attrGenAIUsageInputTokens.Int64(usage.InputTokens),
+		attrGenAIUsageOutputTokens.Int64(usage.OutputTokens),
+	}
+	if stopReason != "" {
+		attrs = append(attrs, attrGenAIResponseFinishReasons.StringSlice([]string{stopReason}))
+	}
+	span.SetAttributes(attrs...)
+}
+
+// setSpanFirstToken records the time to the first token of a stream.
+func setSpanFirstToken(span trace.Span, start time.Time) {
+	span.AddEvent("first_token")
+	span.SetAttributes(attrGenAIResponseTimeToFirstToken.Float64(time.Since(start).Seconds()))
+}
+
+// endSpan records the status and the error of the call, and ends the span.
+func endSpan(span trace.Span, metadata ResponseMetadata, statusCode int, err error) {
+	if statusCode != 0 {
+		span.SetAttributes(attrHTTPResponseStatus.Int(statusCode))
+	}
+	if metadata.RequestID != "" {
+		span.SetAttributes(attrRequestId.String(metadata.RequestID))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,177 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTracedTestClient(t *testing.T, handler http.HandlerFunc) (*Client, *tracetest.InMemoryExporter) {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() { tp.Shutdown(context.Background()) })
+	return newTestClient(t, handler, WithTracerProvider(tp)), exporter
+}
+
+func onlySpan(t *testing.T, exporter *tracetest.InMemoryExporter) tracetest.SpanStub {
+	t.Helper()
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	return spans[0]
+}
+
+func spanAttributes(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestTracingCreateMessages(t *testing.T) {
+	c, exporter := newTracedTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Request-Id", "req_1")
+		w.Write([]byte(`{"id":"msg_1","model":"claude-test-1","stop_reason":"end_turn","content":[],"usage":{"input_tokens":12,"output_tokens":34}}`))
+	})
+
+	_, err := c.CreateMessages(context.Background(), RequestBodyMessages{
+		Model:         "claude-test",
+		MaxTokens:     100,
+		Temperature:   0.5,
+		StopSequences: []string{"END"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	span := onlySpan(t, exporter)
+	if span.Name != "chat claude-test" {
+		t.Errorf("Name = %q, want %q", span.Name, "chat claude-test")
+	}
+	if span.Status.Code != codes.Unset {
+		t.Errorf("Status = %v, want Unset", span.Status.Code)
+	}
+	attrs := spanAttributes(span)
+	want := map[attribute.Key]attribute.Value{
+		"gen_ai.system":                  attribute.StringValue("anthropic"),
+		"gen_ai.operation.name":          attribute.StringValue("chat"),
+		"gen_ai.request.model":           attribute.StringValue("claude-test"),
+		"gen_ai.request.max_tokens":      attribute.IntValue(100),
+		"gen_ai.request.temperature":     attribute.Float64Value(0.5),
+		"gen_ai.request.stop_sequences":  attribute.StringSliceValue([]string{"END"}),
+		"gen_ai.response.id":             attribute.StringValue("msg_1"),
+		"gen_ai.response.model":          attribute.StringValue("claude-test-1"),
+		"gen_ai.response.finish_reasons": attribute.StringSliceValue([]string{"end_turn"}),
+		"gen_ai.usage.input_tokens":      attribute.Int64Value(12),
+		"gen_ai.usage.output_tokens":     attribute.Int64Value(34),
+		"http.response.status_code":      attribute.IntValue(200),
+		"anthropic.request_id":           attribute.StringValue("req_1"),
+	}
+	for k, v := range want {
+		if got, ok := attrs[k]; !ok || got != v {
+			t.Errorf("%s = %v, want %v", k, got.Emit(), v.Emit())
+		}
+	}
+}
+
+func TestTracingCreateMessagesStream(t *testing.T) {
+	c, exporter := newTracedTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeSSE(w,
+			[2]string{"message_start", `{"type":"message_start","message":{"id":"msg_2","model":"claude-test-1","usage":{"input_tokens":7}}}`},
+			[2]string{"content_block_start", `{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`},
+			[2]string{"content_block_delta", `{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Hi"}}`},
+			[2]string{"content_block_stop", `{"type":"content_block_stop","index":0}`},
+			[2]string{"message_delta", `{"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":3}}`},
+			[2]string{"message_stop", `{"type":"message_stop"}`},
+		)
+	})
+
+	stream, err := c.CreateMessagesStream(context.Background(), RequestBodyMessages{Model: "claude-test", MaxTokens: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for {
+		if _, err := stream.Recv(); err != nil {
+			break
+		}
+	}
+	stream.Close()
+
+	span := onlySpan(t, exporter)
+	if span.Name != "chat claude-test" {
+		t.Errorf("Name = %q, want %q", span.Name, "chat claude-test")
+	}
+	if span.Status.Code != codes.Unset {
+		t.Errorf("Status = %v, want Unset", span.Status.Code)
+	}
+	attrs := spanAttributes(span)
+	ttft, ok := attrs["gen_ai.response.time_to_first_token"]
+	if !ok || ttft.AsFloat64() <= 0 {
+		t.Errorf("gen_ai.response.time_to_first_token = %v, want > 0", ttft.Emit())
+	}
+	if len(span.Events) != 1 || span.Events[0].Name != "first_token" {
+		t.Errorf("Events = %v, want a first_token event", span.Events)
+	}
+	want := map[attribute.Key]attribute.Value{
+		"gen_ai.response.id":             attribute.StringValue("msg_2"),
+		"gen_ai.response.finish_reasons": attribute.StringSliceValue([]string{"end_turn"}),
+		"gen_ai.usage.input_tokens":      attribute.Int64Value(7),
+		"gen_ai.usage.output_tokens":     attribute.Int64Value(3),
+	}
+	for k, v := range want {
+		if got, ok := attrs[k]; !ok || got != v {
+			t.Errorf("%s = %v, want %v", k, got.Emit(), v.Emit())
+		}
+	}
+}
+
+func TestTracingErrorStatus(t *testing.T) {
+	c, exporter := newTracedTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"type":"error","error":{"type":"invalid_request_error","message":"max_tokens: field required"}}`))
+	})
+
+	_, err := c.CreateMessages(context.Background(), RequestBodyMessages{Model: "claude-test"})
+	if err == nil {
+		t.Fatal("want an error")
+	}
+
+	span := onlySpan(t, exporter)
+	if span.Status.Code != codes.Error {
+		t.Errorf("Status = %v, want Error", span.Status.Code)
+	}
+	if got := spanAttributes(span)["http.response.status_code"]; got != attribute.IntValue(400) {
+		t.Errorf("http.response.status_code = %v, want 400", got.Emit())
+	}
+}
+
+func TestTracingStreamClosedEarly(t *testing.T) {
+	c, exporter := newTracedTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("event: message_start\ndata: {\"message\":{\"id\":\"msg_3\"}}\n\n"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+
+	stream, err := c.CreateMessagesStream(context.Background(), RequestBodyMessages{Model: "claude-test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	stream.Close()
+
+	span := onlySpan(t, exporter)
+	if span.Status.Code != codes.Error || span.Status.Description != ErrStreamClosed.Error() {
+		t.Errorf("Status = %+v, want Error with ErrStreamClosed", span.Status)
+	}
+}
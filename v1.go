@@ -1,7 +1,11 @@
 package v1
 
 import (
+	"log/slog"
 	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Client struct {
@@ -21,11 +25,27 @@ type ClientConfig struct {
 	ApiKey string
 
 	Version string
-	Beta    string // Using Beta API : anthropic-beta Header
+	Beta    string   // Using Beta API : anthropic-beta Header, comma separated
+	Betas   []string // optional, merged with Beta
 
 	BaseURL    string
 	Endpoint   string
 	HTTPClient *http.Client
+	Timeout    time.Duration // optional, time until the response headers of each attempt are received. the body is not limited
+	Header     http.Header   // optional, extra headers sent with every request
+
+	MaxRetries     int           // retries of 408, 409, 429, 5xx and connection errors, 0 disables retries
+	RetryBaseDelay time.Duration // delay before the first retry, doubled for each retry. default 500ms
+	RetryMaxDelay  time.Duration // default 8s
+	RetryJitter    float64       // 0 to 1, the fraction of the delay that is randomly reduced
+
+	RateLimiter *RateLimiter // optional, throttles messages requests on the client side
+	Middlewares []Middleware // optional, the first one is the outermost
+
+	Logger    *slog.Logger // optional, logs each request, retry and failure
+	LogBodies bool         // optional, also logs headers and bodies at debug level. X-Api-Key is always redacted
+
+	TracerProvider trace.TracerProvider // optional, creates an OpenTelemetry span with gen_ai.* attributes for each call
 }
 
 func defaultConfig(apiKey string) ClientConfig {
@@ -38,18 +58,37 @@ func defaultConfig(apiKey string) ClientConfig {
 		BaseURL:    defaultBaseURL,
 		Endpoint:   defaultEndpoint,
 		HTTPClient: &http.Client{},
+
+		MaxRetries:     defaultMaxRetries,
+		RetryBaseDelay: defaultRetryBaseDelay,
+		RetryMaxDelay:  defaultRetryMaxDelay,
+		RetryJitter:    defaultRetryJitter,
 	}
 }
 
-func NewClient(apiKey string) *Client {
+// NewClient creates a client with the default config.
+// An empty apiKey is read from the ANTHROPIC_API_KEY environment variable, and the base URL
+// from ANTHROPIC_BASE_URL when it is set. The options are applied after that.
+//
+//	c := claude.NewClient("", claude.WithTimeout(time.Minute), claude.WithBeta("output-128k-2025-02-19"))
+func NewClient(apiKey string, opts ...Option) *Client {
+	config := configFromEnv()
+	if apiKey != "" {
+		config.ApiKey = apiKey
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
 	return &Client{
-		config: defaultConfig(apiKey),
+		config: config.withDefaults(),
 	}
 }
 
+// NewClientWithConfig creates a client with the config.
+// Empty Version, BaseURL, Endpoint and HTTPClient fields are set to the defaults.
 func NewClientWithConfig(config ClientConfig) *Client {
 	return &Client{
-		config: config,
+		config: config.withDefaults(),
 	}
 }
 